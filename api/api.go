@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/go-faker/faker/v4"
+	"github.com/jackvonhouse/car-enrichment/pkg/vin"
 	"log"
 	"math/rand/v2"
 	"net/http"
@@ -20,7 +21,31 @@ type Car struct {
 	Mark   string `json:"mark" faker:"word"`
 	Model  string `json:"model" faker:"word"`
 	Year   int    `json:"year" faker:"oneof: 15, 27, 61"`
-	Owner  Owner  `json:"owner"`
+	Vin    string `json:"vin" faker:"-"`
+
+	Color        string `json:"color" faker:"oneof: белый, чёрный, серебристый, синий"`
+	BodyType     string `json:"bodyType" faker:"oneof: седан, хэтчбек, универсал, внедорожник"`
+	FuelType     string `json:"fuelType" faker:"oneof: бензин, дизель, газ"`
+	EngineVolume int    `json:"engineVolume" faker:"oneof: 1596, 1598, 1999"`
+	Power        int    `json:"power" faker:"oneof: 87, 106, 150"`
+	Mileage      int    `json:"mileage" faker:"boundary_start=0, boundary_end=300000"`
+	MileageAt    string `json:"mileageAt" faker:"date"`
+
+	Owner Owner `json:"owner"`
+}
+
+// randomVin собирает VIN российской сборки с корректной контрольной цифрой
+func randomVin() string {
+	const alphabet = "0123456789ABCDEFGHJKLMNPRSTUVWXYZ"
+
+	v := []byte("XTA")
+	for len(v) < 17 {
+		v = append(v, alphabet[rand.IntN(len(alphabet))])
+	}
+
+	v[8] = vin.CheckDigit(string(v))
+
+	return string(v)
 }
 
 func main() {
@@ -46,6 +71,7 @@ func main() {
 		}
 
 		car.RegNum = regNum
+		car.Vin = randomVin()
 
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
@@ -11,6 +11,7 @@ import (
 	"github.com/jackvonhouse/car-enrichment/config"
 	"github.com/jackvonhouse/car-enrichment/internal/infrastructure/server/http"
 	"github.com/jackvonhouse/car-enrichment/pkg/log"
+	"github.com/jackvonhouse/car-enrichment/pkg/shutdown"
 )
 
 type App struct {
@@ -23,6 +24,7 @@ type App struct {
 	config config.Config
 	logger log.Logger
 	server http.Server
+	state  *shutdown.State
 }
 
 func New(
@@ -36,10 +38,16 @@ func New(
 		return App{}, err
 	}
 
-	r := repository.New(i, logger)
-	s := service.New(r, config.API, logger)
+	state := shutdown.NewState(config.Health.ShutdownDelay)
+
+	r := repository.New(i, config, logger)
+	s, err := service.New(i, r, state, config, logger)
+	if err != nil {
+		return App{}, err
+	}
+
 	u := usecase.New(s, logger)
-	t := transport.New(u, logger)
+	t := transport.New(i, u, config, logger)
 
 	httpServer := http.New(t.Router(), config.HTTP)
 
@@ -52,6 +60,7 @@ func New(
 		config:         config,
 		logger:         logger,
 		server:         httpServer,
+		state:          state,
 	}, nil
 }
 
@@ -65,6 +74,13 @@ func (a App) Shutdown(
 	ctx context.Context,
 ) error {
 
+	// /readyz отвечает 503 до остановки сервера
+	a.logger.Info("marking service as not ready..")
+
+	if err := a.state.Shutdown(ctx); err != nil {
+		return err
+	}
+
 	a.logger.Info("http server shutdowning..")
 
 	if err := a.server.Shutdown(ctx); err != nil {
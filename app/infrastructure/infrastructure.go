@@ -3,12 +3,14 @@ package infrastructure
 import (
 	"context"
 	"github.com/jackvonhouse/car-enrichment/config"
+	"github.com/jackvonhouse/car-enrichment/internal/infrastructure/metrics"
 	"github.com/jackvonhouse/car-enrichment/internal/infrastructure/postgres"
 	"github.com/jackvonhouse/car-enrichment/pkg/log"
 )
 
 type Infrastructure struct {
 	Storage postgres.Database
+	Metrics *metrics.Metrics
 }
 
 func New(
@@ -28,5 +30,6 @@ func New(
 
 	return Infrastructure{
 		Storage: db,
+		Metrics: metrics.New(db.Database().DB),
 	}, nil
 }
@@ -3,29 +3,48 @@ package repository
 import (
 	"context"
 	"github.com/jackvonhouse/car-enrichment/app/infrastructure"
+	"github.com/jackvonhouse/car-enrichment/config"
 	"github.com/jackvonhouse/car-enrichment/internal/infrastructure/postgres"
+	"github.com/jackvonhouse/car-enrichment/internal/repository/apikey"
+	"github.com/jackvonhouse/car-enrichment/internal/repository/audit"
 	"github.com/jackvonhouse/car-enrichment/internal/repository/car"
+	"github.com/jackvonhouse/car-enrichment/internal/repository/dictionary"
+	"github.com/jackvonhouse/car-enrichment/internal/repository/health"
 	"github.com/jackvonhouse/car-enrichment/internal/repository/owner"
+	"github.com/jackvonhouse/car-enrichment/internal/repository/quota"
 	"github.com/jackvonhouse/car-enrichment/pkg/log"
 )
 
 type Repository struct {
-	Car   car.Repository
-	Owner owner.Repository
+	Car        car.Repository
+	Owner      owner.Repository
+	Audit      audit.Repository
+	Dictionary dictionary.Repository
+	APIKey     apikey.Repository
+	Quota      quota.Repository
+	Health     health.Repository
 
 	Storage postgres.Database
 }
 
 func New(
 	infrastructure infrastructure.Infrastructure,
+	config config.Config,
 	logger log.Logger,
 ) Repository {
 
 	repositoryLogger := logger.WithField("layer", "repository")
 
+	auditRepository := audit.New(infrastructure.Storage.Database(), repositoryLogger)
+
 	return Repository{
-		Car:   car.New(infrastructure.Storage.Database(), repositoryLogger),
-		Owner: owner.New(infrastructure.Storage.Database(), repositoryLogger),
+		Car:        car.New(infrastructure.Storage.Database(), auditRepository, config.Search, repositoryLogger),
+		Owner:      owner.New(infrastructure.Storage.Database(), auditRepository, repositoryLogger),
+		Audit:      auditRepository,
+		Dictionary: dictionary.New(infrastructure.Storage.Database(), repositoryLogger),
+		APIKey:     apikey.New(infrastructure.Storage.Database(), auditRepository, repositoryLogger),
+		Quota:      quota.New(infrastructure.Storage.Database(), repositoryLogger),
+		Health:     health.New(infrastructure.Storage, repositoryLogger),
 
 		Storage: infrastructure.Storage,
 	}
@@ -1,31 +1,60 @@
 package service
 
 import (
+	"github.com/jackvonhouse/car-enrichment/app/infrastructure"
 	"github.com/jackvonhouse/car-enrichment/app/repository"
 	"github.com/jackvonhouse/car-enrichment/config"
+	"github.com/jackvonhouse/car-enrichment/internal/service/apikey"
+	"github.com/jackvonhouse/car-enrichment/internal/service/audit"
 	"github.com/jackvonhouse/car-enrichment/internal/service/car"
+	"github.com/jackvonhouse/car-enrichment/internal/service/dictionary"
 	"github.com/jackvonhouse/car-enrichment/internal/service/enrichment"
+	"github.com/jackvonhouse/car-enrichment/internal/service/health"
 	"github.com/jackvonhouse/car-enrichment/internal/service/owner"
+	"github.com/jackvonhouse/car-enrichment/internal/service/quota"
+	"github.com/jackvonhouse/car-enrichment/internal/service/token"
 	"github.com/jackvonhouse/car-enrichment/pkg/log"
+	"github.com/jackvonhouse/car-enrichment/pkg/shutdown"
 )
 
 type Service struct {
 	Car        car.Service
 	Owner      owner.Service
 	Enrichment enrichment.Service
+	Audit      audit.Service
+	Dictionary dictionary.Service
+	APIKey     apikey.Service
+	Token      token.Service
+	Quota      quota.Service
+	Health     health.Service
 }
 
 func New(
+	infrastructure infrastructure.Infrastructure,
 	repository repository.Repository,
-	config config.API,
+	state *shutdown.State,
+	config config.Config,
 	logger log.Logger,
-) Service {
+) (Service, error) {
 
 	serviceLogger := logger.WithField("layer", "service")
 
+	tokenService, err := token.New(config.Auth.JWT, serviceLogger)
+	if err != nil {
+		return Service{}, err
+	}
+
+	enrichmentService := enrichment.New(config.API, infrastructure.Metrics, serviceLogger)
+
 	return Service{
-		Enrichment: enrichment.New(config, serviceLogger),
-		Car:        car.New(repository.Car, serviceLogger),
+		Enrichment: enrichmentService,
+		Car:        car.New(repository.Car, infrastructure.Metrics, config.Stats, serviceLogger),
 		Owner:      owner.New(repository.Owner, serviceLogger),
-	}
+		Audit:      audit.New(repository.Audit, serviceLogger),
+		Dictionary: dictionary.New(repository.Dictionary, serviceLogger),
+		APIKey:     apikey.New(repository.APIKey, config.Auth, serviceLogger),
+		Token:      tokenService,
+		Quota:      quota.New(repository.Quota, config.Quota, serviceLogger),
+		Health:     health.New(repository.Health, enrichmentService, state, config.Health, serviceLogger),
+	}, nil
 }
@@ -2,12 +2,23 @@ package transport
 
 import (
 	"github.com/gorilla/mux"
+	"github.com/jackvonhouse/car-enrichment/app/infrastructure"
 	"github.com/jackvonhouse/car-enrichment/app/usecase"
+	"github.com/jackvonhouse/car-enrichment/config"
 	_ "github.com/jackvonhouse/car-enrichment/docs"
+	"github.com/jackvonhouse/car-enrichment/internal/identity"
+	httptransport "github.com/jackvonhouse/car-enrichment/internal/transport"
+	"github.com/jackvonhouse/car-enrichment/internal/transport/apikey"
+	"github.com/jackvonhouse/car-enrichment/internal/transport/audit"
 	"github.com/jackvonhouse/car-enrichment/internal/transport/car"
+	"github.com/jackvonhouse/car-enrichment/internal/transport/dictionary"
+	"github.com/jackvonhouse/car-enrichment/internal/transport/health"
 	"github.com/jackvonhouse/car-enrichment/internal/transport/router"
+	"github.com/jackvonhouse/car-enrichment/internal/transport/search"
+	"github.com/jackvonhouse/car-enrichment/internal/transport/stats"
 	"github.com/jackvonhouse/car-enrichment/pkg/log"
 	"github.com/swaggo/http-swagger/v2"
+	"net/http"
 )
 
 type Transport struct {
@@ -15,25 +26,53 @@ type Transport struct {
 }
 
 func New(
+	infrastructure infrastructure.Infrastructure,
 	useCase usecase.UseCase,
+	config config.Config,
 	logger log.Logger,
 ) Transport {
 
 	transportLogger := logger.WithField("layer", "transport")
 
+	httptransport.SetErrorFormat(config.Errors.Format)
+
 	r := router.New("/api/v1")
 
+	r.UseRoot(
+		router.RequestID,
+		router.AccessLog(transportLogger),
+	)
+
+	r.Use(
+		router.Metrics(infrastructure.Metrics),
+		router.Recover(transportLogger),
+		router.AuthFailures(config.RateLimit),
+		router.Auth(config.Auth.Enabled, useCase.APIKey, useCase.Token, transportLogger),
+		router.RateLimit(config.RateLimit),
+	)
+
 	r.Handle(map[string]router.Handlify{
-		"/car": car.New(useCase.Car, transportLogger),
+		"/car":        car.New(useCase.Car, transportLogger),
+		"/audit":      audit.New(useCase.Audit, transportLogger),
+		"/search":     search.New(useCase.Car, transportLogger),
+		"/dictionary": dictionary.New(useCase.Dictionary, transportLogger),
+		"/stats":      stats.New(useCase.Car, transportLogger),
+		"/apikey":     apikey.New(useCase.APIKey, transportLogger),
 	})
 
+	health.New(useCase.Health, transportLogger).Handle(r.Root())
+
+	r.Root().
+		Handle("/metrics", infrastructure.Metrics.Handler()).
+		Methods(http.MethodGet)
+
 	r.Router().
 		PathPrefix("/swagger").
-		Handler(httpSwagger.WrapHandler)
+		Handler(router.RequireScope(identity.ScopeRead)(httpSwagger.WrapHandler))
 
 	return Transport{
 		router: r,
 	}
 }
 
-func (t Transport) Router() *mux.Router { return t.router.Router() }
+func (t Transport) Router() *mux.Router { return t.router.Root() }
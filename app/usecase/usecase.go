@@ -2,12 +2,22 @@ package usecase
 
 import (
 	"github.com/jackvonhouse/car-enrichment/app/service"
+	"github.com/jackvonhouse/car-enrichment/internal/usecase/apikey"
+	"github.com/jackvonhouse/car-enrichment/internal/usecase/audit"
 	"github.com/jackvonhouse/car-enrichment/internal/usecase/car"
+	"github.com/jackvonhouse/car-enrichment/internal/usecase/dictionary"
+	"github.com/jackvonhouse/car-enrichment/internal/usecase/health"
+	"github.com/jackvonhouse/car-enrichment/internal/usecase/token"
 	"github.com/jackvonhouse/car-enrichment/pkg/log"
 )
 
 type UseCase struct {
-	Car car.UseCase
+	Car        car.UseCase
+	Audit      audit.UseCase
+	Dictionary dictionary.UseCase
+	APIKey     apikey.UseCase
+	Token      token.UseCase
+	Health     health.UseCase
 }
 
 func New(
@@ -18,6 +28,11 @@ func New(
 	useCaseLogger := logger.WithField("layer", "usecase")
 
 	return UseCase{
-		Car: car.New(service.Car, service.Owner, service.Enrichment, useCaseLogger),
+		Car:        car.New(service.Car, service.Owner, service.Enrichment, service.Dictionary, service.Quota, useCaseLogger),
+		Audit:      audit.New(service.Audit, useCaseLogger),
+		Dictionary: dictionary.New(service.Dictionary, useCaseLogger),
+		APIKey:     apikey.New(service.APIKey, useCaseLogger),
+		Token:      token.New(service.Token, useCaseLogger),
+		Health:     health.New(service.Health, useCaseLogger),
 	}
 }
@@ -31,6 +31,14 @@ func getConfigPath() string {
 // @description	Простейшее API для каталога автомобилей
 // @host		localhost:8081
 // @BasePath	/api/v1
+// @securityDefinitions.apikey	ApiKeyAuth
+// @in							header
+// @name						X-API-Key
+// @description				Ключ API, выдаётся через /apikey
+// @securityDefinitions.apikey	BearerAuth
+// @in							header
+// @name						Authorization
+// @description				Токен пользователя JWT: "Bearer <токен>"
 func main() {
 	ctx, cancel := context.WithCancel(context.Background())
 
@@ -4,9 +4,11 @@ import (
 	"fmt"
 	"github.com/jackvonhouse/car-enrichment/internal/errors"
 	"github.com/jackvonhouse/car-enrichment/pkg/log"
+	"github.com/jackvonhouse/car-enrichment/pkg/ratelimit"
 	"github.com/spf13/viper"
 	"path/filepath"
 	"strings"
+	"time"
 )
 
 type Database struct {
@@ -36,10 +38,98 @@ type API struct {
 	Url string
 }
 
+type Search struct {
+	// SimilarityThreshold — минимальное сходство (0..1) для нечёткого поиска
+	SimilarityThreshold float64
+}
+
+type Stats struct {
+	// CacheTTL — время жизни закэшированной статистики, 0 отключает кэш
+	CacheTTL time.Duration
+}
+
+type Auth struct {
+	// Enabled включает проверку ключей API и токенов, без неё
+	// все запросы анонимны и имеют все права
+	Enabled bool
+	// BootstrapKey — ключ с правом admin, который не хранится в базе,
+	// нужен, чтобы выдать первые ключи
+	BootstrapKey string
+
+	JWT JWT
+}
+
+// JWT — проверка токенов пользователей. Токены HS256 проверяются
+// секретом, RS256 — открытым ключом в PEM или ключами из файла JWKS
+type JWT struct {
+	Enabled bool
+
+	Secret        string
+	PublicKeyFile string
+	JWKSFile      string
+
+	// Issuer и Audience проверяются, если заданы
+	Issuer   string
+	Audience string
+
+	// RoleClaim — claim с ролью: строкой или массивом строк
+	RoleClaim string
+	Leeway    time.Duration
+}
+
+type RateLimit struct {
+	Enabled bool
+	// Default — лимит маршрутов, которых нет в Routes, пустой не ограничивает
+	Default ratelimit.Limit
+	Routes  []RouteLimit
+	// AuthFailures — лимит неудачных проверок ключа или токена
+	// с одного IP-адреса, пустой не ограничивает
+	AuthFailures ratelimit.Limit
+}
+
+type RouteLimit struct {
+	Method string
+	// Path — шаблон маршрута без регулярных выражений, например /api/v1/car/{id}
+	Path  string
+	Limit ratelimit.Limit `mapstructure:",squash"`
+}
+
+type Quota struct {
+	// DailyEnrichment — сколько номеров клиент может отправить на обогащение
+	// за сутки по UTC, 0 снимает ограничение
+	DailyEnrichment int
+}
+
+type Health struct {
+	// ProbeTTL — как долго используется результат проверки внешнего API,
+	// чтобы частые проверки готовности не нагружали его
+	ProbeTTL time.Duration
+	// ShutdownDelay — пауза между отметкой о неготовности и остановкой сервера
+	ShutdownDelay time.Duration
+}
+
+// Errors — формат ответов с ошибкой: problem — application/problem+json
+// по RFC 7807, legacy — прежний {"error": "..."}
+type Errors struct {
+	Format string
+}
+
+const (
+	ErrorFormatProblem = "problem"
+	ErrorFormatLegacy  = "legacy"
+)
+
 type Config struct {
-	Database Database
-	HTTP     Server
-	API      API
+	Database  Database
+	HTTP      Server
+	API       API
+	Search    Search
+	Stats     Stats
+	Auth      Auth
+	RateLimit RateLimit
+	Quota     Quota
+	Errors    Errors
+	Health    Health
 }
 
 func New(
@@ -64,6 +154,53 @@ func New(
 	pgPrefix := "database.postgres"
 	httpPrefix := "server.http"
 	apiPrefix := "api"
+	searchPrefix := "search"
+	statsPrefix := "stats"
+	authPrefix := "auth"
+	rateLimitPrefix := "rate_limit"
+	quotaPrefix := "quota"
+	errorsPrefix := "errors"
+	healthPrefix := "health"
+
+	viper.SetDefault(fmt.Sprintf("%s.similarity_threshold", searchPrefix), 0.3)
+	viper.SetDefault(fmt.Sprintf("%s.cache_ttl", statsPrefix), 30*time.Second)
+	// Без явного включения прежние установки продолжают работать без ключей
+	viper.SetDefault(fmt.Sprintf("%s.enabled", authPrefix), false)
+	viper.SetDefault(fmt.Sprintf("%s.jwt.role_claim", authPrefix), "role")
+	viper.SetDefault(fmt.Sprintf("%s.jwt.leeway", authPrefix), 30*time.Second)
+	viper.SetDefault(fmt.Sprintf("%s.format", errorsPrefix), ErrorFormatProblem)
+	viper.SetDefault(fmt.Sprintf("%s.probe_ttl", healthPrefix), 30*time.Second)
+	viper.SetDefault(fmt.Sprintf("%s.auth_failures.requests", rateLimitPrefix), 10)
+	viper.SetDefault(fmt.Sprintf("%s.auth_failures.period", rateLimitPrefix), time.Minute)
+
+	errorFormat := viper.GetString(fmt.Sprintf("%s.format", errorsPrefix))
+	if errorFormat != ErrorFormatProblem && errorFormat != ErrorFormatLegacy {
+		configLogger.Warnf("unknown error format: %s", errorFormat)
+
+		return Config{}, errors.ErrInvalid.New("unknown error format " + errorFormat)
+	}
+
+	rateLimit := RateLimit{
+		Enabled: viper.GetBool(fmt.Sprintf("%s.enabled", rateLimitPrefix)),
+	}
+
+	if err := viper.UnmarshalKey(fmt.Sprintf("%s.default", rateLimitPrefix), &rateLimit.Default); err != nil {
+		configLogger.Warnf("invalid default rate limit: %s", err)
+
+		return Config{}, errors.ErrInvalid.New("invalid default rate limit").Wrap(err)
+	}
+
+	if err := viper.UnmarshalKey(fmt.Sprintf("%s.routes", rateLimitPrefix), &rateLimit.Routes); err != nil {
+		configLogger.Warnf("invalid route rate limits: %s", err)
+
+		return Config{}, errors.ErrInvalid.New("invalid route rate limits").Wrap(err)
+	}
+
+	if err := viper.UnmarshalKey(fmt.Sprintf("%s.auth_failures", rateLimitPrefix), &rateLimit.AuthFailures); err != nil {
+		configLogger.Warnf("invalid auth failures rate limit: %s", err)
+
+		return Config{}, errors.ErrInvalid.New("invalid auth failures rate limit").Wrap(err)
+	}
 
 	return Config{
 		Database: Database{
@@ -82,5 +219,44 @@ func New(
 		API: API{
 			Url: viper.GetString(fmt.Sprintf("%s.url", apiPrefix)),
 		},
+
+		Search: Search{
+			SimilarityThreshold: viper.GetFloat64(fmt.Sprintf("%s.similarity_threshold", searchPrefix)),
+		},
+
+		Stats: Stats{
+			CacheTTL: viper.GetDuration(fmt.Sprintf("%s.cache_ttl", statsPrefix)),
+		},
+
+		Auth: Auth{
+			Enabled:      viper.GetBool(fmt.Sprintf("%s.enabled", authPrefix)),
+			BootstrapKey: viper.GetString(fmt.Sprintf("%s.bootstrap_key", authPrefix)),
+
+			JWT: JWT{
+				Enabled:       viper.GetBool(fmt.Sprintf("%s.jwt.enabled", authPrefix)),
+				Secret:        viper.GetString(fmt.Sprintf("%s.jwt.secret", authPrefix)),
+				PublicKeyFile: viper.GetString(fmt.Sprintf("%s.jwt.public_key_file", authPrefix)),
+				JWKSFile:      viper.GetString(fmt.Sprintf("%s.jwt.jwks_file", authPrefix)),
+				Issuer:        viper.GetString(fmt.Sprintf("%s.jwt.issuer", authPrefix)),
+				Audience:      viper.GetString(fmt.Sprintf("%s.jwt.audience", authPrefix)),
+				RoleClaim:     viper.GetString(fmt.Sprintf("%s.jwt.role_claim", authPrefix)),
+				Leeway:        viper.GetDuration(fmt.Sprintf("%s.jwt.leeway", authPrefix)),
+			},
+		},
+
+		RateLimit: rateLimit,
+
+		Quota: Quota{
+			DailyEnrichment: viper.GetInt(fmt.Sprintf("%s.daily_enrichment", quotaPrefix)),
+		},
+
+		Errors: Errors{
+			Format: errorFormat,
+		},
+
+		Health: Health{
+			ProbeTTL:      viper.GetDuration(fmt.Sprintf("%s.probe_ttl", healthPrefix)),
+			ShutdownDelay: viper.GetDuration(fmt.Sprintf("%s.shutdown_delay", healthPrefix)),
+		},
 	}, nil
 }
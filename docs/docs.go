@@ -15,9 +15,17 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/car": {
+        "/apikey": {
             "get": {
-                "description": "Получение автомобилей с возможностью фильтрации",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Получение всех ключей, включая отозванные и истёкшие.\nВместо ключа показывается его начало",
                 "consumes": [
                     "application/json"
                 ],
@@ -25,101 +33,49 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Автомобиль"
-                ],
-                "summary": "Получить автомобилей",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Лимит",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Смещение",
-                        "name": "offset",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Гос. номер",
-                        "name": "regNum",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Марка",
-                        "name": "mark",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Модель",
-                        "name": "model",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Год",
-                        "name": "year",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Имя владельца",
-                        "name": "ownerName",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Фамилия владельца",
-                        "name": "ownerSurname",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Отчество владельца",
-                        "name": "ownerPatronymic",
-                        "in": "query"
-                    }
+                    "Ключи API"
                 ],
+                "summary": "Получить ключи API",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/github_com_jackvonhouse_car-enrichment_internal_dto.Car"
+                                "$ref": "#/definitions/github_com_jackvonhouse_car-enrichment_internal_dto.APIKey"
                             }
                         }
                     },
-                    "404": {
-                        "description": "Автомобили отсутствуют",
+                    "401": {
+                        "description": "Нет ключа или ключ недействителен",
                         "schema": {
-                            "type": "object",
-                            "properties": {
-                                "error": {
-                                    "type": "string"
-                                }
-                            }
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет права admin",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "500": {
                         "description": "Неизвестная ошибка",
                         "schema": {
-                            "type": "object",
-                            "properties": {
-                                "error": {
-                                    "type": "string"
-                                }
-                            }
+                            "$ref": "#/definitions/Problem"
                         }
                     }
                 }
             },
             "post": {
-                "description": "Создание и обогощение автомобиля",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Выдача ключа с правами: read — чтение, write — создание и изменение,\ndelete — удаление, admin — все права и управление ключами.\nКлюч возвращается только в этом ответе, сохраните его.\nБез expiresAt ключ бессрочный",
                 "consumes": [
                     "application/json"
                 ],
@@ -127,17 +83,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Автомобиль"
+                    "Ключи API"
                 ],
-                "summary": "Создание автомобиля",
+                "summary": "Выдать ключ API",
                 "parameters": [
                     {
-                        "description": "Массив гос. номеров",
+                        "description": "Ключ",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/github_com_jackvonhouse_car-enrichment_internal_dto.CreateCar"
+                            "$ref": "#/definitions/github_com_jackvonhouse_car-enrichment_internal_dto.CreateAPIKey"
                         }
                     }
                 ],
@@ -145,42 +101,59 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "properties": {
-                                "result": {
-                                    "type": "boolean"
-                                }
-                            }
+                            "$ref": "#/definitions/github_com_jackvonhouse_car-enrichment_internal_dto.IssuedAPIKey"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный JSON",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Нет ключа или ключ недействителен",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет права admin",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "409": {
-                        "description": "Автомобиль или владелец уже существует",
+                        "description": "Ключ с таким именем уже существует",
                         "schema": {
-                            "type": "object",
-                            "properties": {
-                                "error": {
-                                    "type": "string"
-                                }
-                            }
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Некорректное имя, права или срок",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "500": {
                         "description": "Неизвестная ошибка",
                         "schema": {
-                            "type": "object",
-                            "properties": {
-                                "error": {
-                                    "type": "string"
-                                }
-                            }
+                            "$ref": "#/definitions/Problem"
                         }
                     }
                 }
             }
         },
-        "/car/{id}": {
-            "put": {
-                "description": "Обновление автомобиля",
+        "/apikey/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Отзыв ключа. Отозванный ключ остаётся в списке и не принимается",
                 "consumes": [
                     "application/json"
                 ],
@@ -188,22 +161,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Автомобиль"
+                    "Ключи API"
                 ],
-                "summary": "Обновить автомобиль",
+                "summary": "Отозвать ключ API",
                 "parameters": [
-                    {
-                        "description": "Данные об автомобиле",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/github_com_jackvonhouse_car-enrichment_internal_dto.Car"
-                        }
-                    },
                     {
                         "type": "integer",
-                        "description": "Идентификатор автомобиля",
+                        "description": "Идентификатор ключа",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -221,43 +185,50 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "Автомобиль или владелец не найдены",
+                    "400": {
+                        "description": "Некорректный идентификатор",
                         "schema": {
-                            "type": "object",
-                            "properties": {
-                                "error": {
-                                    "type": "string"
-                                }
-                            }
+                            "$ref": "#/definitions/Problem"
                         }
                     },
-                    "409": {
-                        "description": "Автомобиль уже существует",
+                    "401": {
+                        "description": "Нет ключа или ключ недействителен",
                         "schema": {
-                            "type": "object",
-                            "properties": {
-                                "error": {
-                                    "type": "string"
-                                }
-                            }
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет права admin",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Ключ не найден или уже отозван",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "500": {
                         "description": "Неизвестная ошибка",
                         "schema": {
-                            "type": "object",
-                            "properties": {
-                                "error": {
-                                    "type": "string"
-                                }
-                            }
+                            "$ref": "#/definitions/Problem"
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Удаление автомобиля",
+            }
+        },
+        "/audit": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Получение журнала изменений автомобилей, владельцев и ключей API.\nДействия: create, update, delete, revoke и transfer — передача\nавтомобиля другому владельцу",
                 "consumes": [
                     "application/json"
                 ],
@@ -265,90 +236,2100 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Автомобиль"
+                    "Аудит"
                 ],
-                "summary": "Удалить автомобиль",
+                "summary": "Получить журнал аудита",
                 "parameters": [
                     {
+                        "maximum": 100,
+                        "minimum": 1,
                         "type": "integer",
-                        "description": "Идентификатор автомобиля",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "default": 10,
+                        "description": "Лимит, от 1 до 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Смещение",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "car",
+                            "owner",
+                            "api_key"
+                        ],
+                        "type": "string",
+                        "description": "Сущность",
+                        "name": "entity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор сущности",
+                        "name": "entityId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Автор изменения",
+                        "name": "actor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало периода (RFC 3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Конец периода (RFC 3339)",
+                        "name": "to",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "properties": {
-                                "result": {
-                                    "type": "boolean"
-                                }
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_jackvonhouse_car-enrichment_internal_dto.Audit"
                             }
                         }
                     },
-                    "404": {
-                        "description": "Автомобиль не найден",
+                    "422": {
+                        "description": "Некорректный фильтр или пагинация",
                         "schema": {
-                            "type": "object",
-                            "properties": {
-                                "error": {
-                                    "type": "string"
-                                }
-                            }
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "500": {
                         "description": "Неизвестная ошибка",
                         "schema": {
-                            "type": "object",
-                            "properties": {
-                                "error": {
-                                    "type": "string"
-                                }
-                            }
+                            "$ref": "#/definitions/Problem"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "github_com_jackvonhouse_car-enrichment_internal_dto.Car": {
-            "type": "object",
-            "properties": {
-                "id": {
-                    "type": "integer"
-                },
-                "mark": {
-                    "type": "string"
-                },
-                "model": {
-                    "type": "string"
-                },
-                "owner": {
-                    "$ref": "#/definitions/github_com_jackvonhouse_car-enrichment_internal_dto.Owner"
-                },
-                "regNum": {
-                    "type": "string"
-                },
-                "year": {
-                    "type": "integer"
-                }
-            }
         },
-        "github_com_jackvonhouse_car-enrichment_internal_dto.CreateCar": {
-            "type": "object",
-            "properties": {
-                "reg_numbers": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
+        "/car": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
                     }
-                }
-            }
+                ],
+                "description": "Получение автомобилей с возможностью фильтрации.\nОтвет оборачивается в страницу с общим количеством и ссылками.\nДля прежнего формата (массив) передайте \"Accept: application/json; version=1\".\nС \"Accept: application/x-ndjson\" все подходящие автомобили отдаются потоком,\nпо одному объекту на строку, limit, offset и cursor при этом не учитываются.\nФильтры задаются как \"поле=значение\" или \"поле[оператор]=значение\".\nОператоры текстовых полей: eq, ne, in, like (по умолчанию), prefix, similar.\nlike и prefix не учитывают регистр и диакритику (\"ё\" равно \"е\"),\nsimilar ищет нечётко по триграммному сходству, например \"ownerSurname[similar]=Иванов\".\nОператоры года: eq (по умолчанию), ne, in, gte, lte, between.\nЗначения in и between перечисляются через запятую: \"year[between]=2010,2015\"",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "Автомобиль"
+                ],
+                "summary": "Получить автомобилей",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "application/json; version=2",
+                        "description": "Версия формата ответа",
+                        "name": "Accept",
+                        "in": "header"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Лимит, от 1 до 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Смещение",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы (nextCursor), несовместим с offset",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "year,-mark",
+                        "description": "Сортировка через запятую, '-' — по убыванию: id, regNum, mark, model, year, ownerName, ownerSurname, ownerPatronymic",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Гос. номер",
+                        "name": "regNum",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Марка",
+                        "name": "mark",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Модель",
+                        "name": "model",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Год",
+                        "name": "year",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Код региона, точное совпадение, операторы eq, ne, in",
+                        "name": "region",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "VIN",
+                        "name": "vin",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Цвет",
+                        "name": "color",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "sedan",
+                            "hatchback",
+                            "liftback",
+                            "wagon",
+                            "suv",
+                            "coupe",
+                            "convertible",
+                            "minivan",
+                            "pickup",
+                            "van"
+                        ],
+                        "type": "string",
+                        "description": "Тип кузова, операторы eq, ne, in",
+                        "name": "bodyType",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "petrol",
+                            "diesel",
+                            "gas",
+                            "hybrid",
+                            "electric"
+                        ],
+                        "type": "string",
+                        "description": "Тип топлива, операторы eq, ne, in",
+                        "name": "fuelType",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Объём двигателя, см³",
+                        "name": "engineVolume",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Мощность, л. с.",
+                        "name": "power",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Пробег, км",
+                        "name": "mileage",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата пробега (2006-01-02), операторы eq, gte, lte, between",
+                        "name": "mileageAt",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Имя владельца",
+                        "name": "ownerName",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Фамилия владельца",
+                        "name": "ownerSurname",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Отчество владельца",
+                        "name": "ownerPatronymic",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_jackvonhouse_car-enrichment_internal_transport.Page-github_com_jackvonhouse_car-enrichment_internal_dto_Car"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный курсор",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Нет ключа или токена, либо они недействительны",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Автомобили отсутствуют",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Некорректный фильтр, сортировка или пагинация",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Неизвестная ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Создание и обогощение автомобиля.\nНомера проверяются по ГОСТ Р 50577 и сохраняются в каноническом виде:\nбез пробелов, в верхнем регистре, похожие латинские буквы заменяются кириллицей.\nКаждый номер списывается из суточной квоты обогащения клиента",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Автомобиль"
+                ],
+                "summary": "Создание автомобиля",
+                "parameters": [
+                    {
+                        "description": "Массив гос. номеров",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_jackvonhouse_car-enrichment_internal_dto.CreateCar"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "result": {
+                                    "type": "boolean"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный JSON или неизвестное поле",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Нет ключа или токена, либо они недействительны",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Автомобиль или владелец уже существует",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Пустой список или некорректные гос. номера",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "429": {
+                        "description": "Превышен лимит запросов или суточная квота обогащения",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Неизвестная ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/car/export": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Выгрузка всех автомобилей, подходящих под фильтры, в CSV или XLSX.\nФильтры и сортировка те же, что и при получении автомобилей, пагинации нет.\nСтроки читаются из базы и отправляются по мере чтения.\nСтолбцы: id, regNum, mark, model, year, region, regionSubject, vin, color, bodyType,\nfuelType, engineVolume, power, mileage, mileageAt, ownerId, ownerName, ownerSurname, ownerPatronymic",
+                "produces": [
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "Автомобиль"
+                ],
+                "summary": "Выгрузить автомобили",
+                "parameters": [
+                    {
+                        "enum": [
+                            "csv",
+                            "xlsx"
+                        ],
+                        "type": "string",
+                        "default": "csv",
+                        "description": "Формат",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "regNum,mark,model,ownerSurname",
+                        "description": "Столбцы через запятую, по умолчанию все",
+                        "name": "columns",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Сортировка через запятую, '-' — по убыванию: id, regNum, mark, model, year, ownerName, ownerSurname, ownerPatronymic",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Гос. номер",
+                        "name": "regNum",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Марка",
+                        "name": "mark",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Модель",
+                        "name": "model",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Год",
+                        "name": "year",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Код региона, точное совпадение, операторы eq, ne, in",
+                        "name": "region",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "401": {
+                        "description": "Нет ключа или токена, либо они недействительны",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Некорректный формат, столбец, фильтр или сортировка",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Неизвестная ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/car/import": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Загрузка автомобилей из CSV: multipart/form-data с полем file или тело text/csv.\nПервая строка — заголовок со столбцами как в выгрузке, обязателен regNum.\nСтрока с одним гос. номером обогащается из внешнего API, если передан enrich=true.\nПолная строка требует mark, model, ownerName и ownerSurname,\nостальные столбцы необязательны: year, vin, color, bodyType, fuelType, engineVolume,\npower, mileage, mileageAt (2006-01-02), ownerPatronymic.\nСтолбцы id, region, regionSubject и ownerId выгрузки пропускаются.\nКаждая строка проверяется и сохраняется отдельно, результат возвращается построчно.\nС dryRun=true строки только проверяются, ничего не записывается и API не вызывается.\nОбогащаемые номера списываются из суточной квоты, строки сверх квоты отклоняются",
+                "consumes": [
+                    "multipart/form-data",
+                    "text/csv"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Автомобиль"
+                ],
+                "summary": "Загрузить автомобили",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "CSV-файл",
+                        "name": "file",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Только проверить",
+                        "name": "dryRun",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Обогащать строки с одним гос. номером",
+                        "name": "enrich",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": ",",
+                        "description": "Разделитель столбцов",
+                        "name": "delimiter",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_jackvonhouse_car-enrichment_internal_dto.ImportResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный файл или заголовок",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Нет ключа или токена, либо они недействительны",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "413": {
+                        "description": "Слишком большой файл",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Некорректный параметр",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Неизвестная ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/car/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Обновление автомобиля.\nVIN проверяется по ISO 3779, контрольная цифра обязательна для VIN Северной Америки.\nМарка и модель приводятся к названиям из справочника, markKnown и modelKnown\nпоказывают, найдены ли они в нём.\nПробег без даты считается полученным в момент запроса.\nДругой owner.id передаёт автомобиль этому владельцу, имя владельца\nпри этом не меняется. Без owner.id или с прежним поля владельца\nизменяют текущего владельца",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Автомобиль"
+                ],
+                "summary": "Обновить автомобиль",
+                "parameters": [
+                    {
+                        "description": "Данные об автомобиле",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_jackvonhouse_car-enrichment_internal_dto.Car"
+                        }
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор автомобиля",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "result": {
+                                    "type": "boolean"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Нет ключа или токена, либо они недействительны",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Автомобиль или владелец не найдены",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Автомобиль уже существует",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Неизвестная ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Удаление автомобиля",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Автомобиль"
+                ],
+                "summary": "Удалить автомобиль",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор автомобиля",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "result": {
+                                    "type": "boolean"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Нет ключа или токена, либо они недействительны",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Автомобиль не найден",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Неизвестная ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/dictionary/mark": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Получение справочника марок с синонимами",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Справочник"
+                ],
+                "summary": "Получить марки",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_jackvonhouse_car-enrichment_internal_dto.Mark"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Неизвестная ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Добавление марки в справочник. Название и синонимы сравниваются\nбез учёта регистра, диакритики, пробелов и дефисов",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Справочник"
+                ],
+                "summary": "Добавить марку",
+                "parameters": [
+                    {
+                        "description": "Марка",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_jackvonhouse_car-enrichment_internal_dto.CreateMark"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "id": {
+                                    "type": "integer"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный JSON",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет права admin",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Марка или синоним уже существует",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Пустое название",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Неизвестная ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/dictionary/mark/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Удаление марки вместе с моделями и синонимами.\nАвтомобили этой марки становятся неизвестными для справочника",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Справочник"
+                ],
+                "summary": "Удалить марку",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор марки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "result": {
+                                    "type": "boolean"
+                                }
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Нет права admin",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Марка не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Неизвестная ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/dictionary/mark/{id}/alias": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Справочник"
+                ],
+                "summary": "Добавить синоним марки",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор марки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Синоним",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_jackvonhouse_car-enrichment_internal_dto.CreateAlias"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "result": {
+                                    "type": "boolean"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный JSON",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет права admin",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Марка не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Синоним уже существует",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Пустой синоним",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Неизвестная ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/dictionary/mark/{id}/alias/{alias}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Справочник"
+                ],
+                "summary": "Удалить синоним марки",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор марки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Синоним",
+                        "name": "alias",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "result": {
+                                    "type": "boolean"
+                                }
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Нет права admin",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Синоним не найден",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Неизвестная ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/dictionary/mark/{id}/model": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Получение моделей марки с синонимами",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Справочник"
+                ],
+                "summary": "Получить модели марки",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор марки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_jackvonhouse_car-enrichment_internal_dto.Model"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Неизвестная ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Добавление модели марки в справочник",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Справочник"
+                ],
+                "summary": "Добавить модель",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор марки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Модель",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_jackvonhouse_car-enrichment_internal_dto.CreateModel"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "id": {
+                                    "type": "integer"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный JSON",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет права admin",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Марка не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Модель или синоним уже существует",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Пустое название",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Неизвестная ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/dictionary/model/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Удаление модели вместе с синонимами.\nАвтомобили этой модели становятся неизвестными для справочника",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Справочник"
+                ],
+                "summary": "Удалить модель",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор модели",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "result": {
+                                    "type": "boolean"
+                                }
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Нет права admin",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Модель не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Неизвестная ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/dictionary/model/{id}/alias": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Справочник"
+                ],
+                "summary": "Добавить синоним модели",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор модели",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Синоним",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_jackvonhouse_car-enrichment_internal_dto.CreateAlias"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "result": {
+                                    "type": "boolean"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный JSON",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет права admin",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Модель не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Синоним уже существует",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Пустой синоним",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Неизвестная ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/dictionary/model/{id}/alias/{alias}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Справочник"
+                ],
+                "summary": "Удалить синоним модели",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Идентификатор модели",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Синоним",
+                        "name": "alias",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "result": {
+                                    "type": "boolean"
+                                }
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Нет права admin",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Синоним не найден",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Неизвестная ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/search": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Полнотекстовый поиск одновременно по гос. номеру, VIN, марке, модели и ФИО владельца.\nСлова ищутся по префиксу, номер и VIN — также по подстроке.\nРезультаты упорядочены по релевантности, matched содержит совпавшие поля",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Поиск"
+                ],
+                "summary": "Поиск автомобилей",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Строка поиска",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Лимит, от 1 до 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Смещение",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_jackvonhouse_car-enrichment_internal_dto.SearchResult"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Пустой запрос или некорректная пагинация",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Неизвестная ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/stats/cars": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Количество автомобилей и распределения по марке, модели, интервалам годов выпуска,\nрегиону и владельцу. Принимает те же фильтры, что и получение автомобилей.\nНеизвестные значения попадают в группу \"unknown\".\nРезультат кэшируется на короткое время",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Статистика"
+                ],
+                "summary": "Статистика автомобилей",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 5,
+                        "description": "Ширина интервала годов выпуска",
+                        "name": "yearBucket",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Число групп по марке, модели, региону и владельцу",
+                        "name": "top",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Гос. номер",
+                        "name": "regNum",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Марка",
+                        "name": "mark",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Модель",
+                        "name": "model",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Год",
+                        "name": "year",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Код региона, точное совпадение, операторы eq, ne, in",
+                        "name": "region",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Фамилия владельца",
+                        "name": "ownerSurname",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_jackvonhouse_car-enrichment_internal_dto.CarStats"
+                        }
+                    },
+                    "422": {
+                        "description": "Некорректная группировка или фильтр",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Неизвестная ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "FieldError": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "Problem": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "detail": {
+                    "type": "string"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/FieldError"
+                    }
+                },
+                "requestId": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_jackvonhouse_car-enrichment_internal_dto.APIKey": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "expiresAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "description": "Prefix — начало ключа, сам ключ после выдачи не хранится",
+                    "type": "string"
+                },
+                "revokedAt": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "enum": [
+                            "read",
+                            "write",
+                            "delete",
+                            "admin"
+                        ],
+                        "$ref": "#/definitions/github_com_jackvonhouse_car-enrichment_internal_identity.Scope"
+                    }
+                }
+            }
+        },
+        "github_com_jackvonhouse_car-enrichment_internal_dto.Audit": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "actor": {
+                    "type": "string"
+                },
+                "after": {
+                    "type": "object"
+                },
+                "before": {
+                    "type": "object"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "entity": {
+                    "type": "string"
+                },
+                "entityId": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_jackvonhouse_car-enrichment_internal_dto.BodyType": {
+            "type": "string",
+            "enum": [
+                "sedan",
+                "hatchback",
+                "liftback",
+                "wagon",
+                "suv",
+                "coupe",
+                "convertible",
+                "minivan",
+                "pickup",
+                "van"
+            ],
+            "x-enum-varnames": [
+                "BodyTypeSedan",
+                "BodyTypeHatchback",
+                "BodyTypeLiftback",
+                "BodyTypeWagon",
+                "BodyTypeSUV",
+                "BodyTypeCoupe",
+                "BodyTypeConvertible",
+                "BodyTypeMinivan",
+                "BodyTypePickup",
+                "BodyTypeVan"
+            ]
+        },
+        "github_com_jackvonhouse_car-enrichment_internal_dto.Car": {
+            "type": "object",
+            "properties": {
+                "bodyType": {
+                    "enum": [
+                        "sedan",
+                        "hatchback",
+                        "liftback",
+                        "wagon",
+                        "suv",
+                        "coupe",
+                        "convertible",
+                        "minivan",
+                        "pickup",
+                        "van"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_jackvonhouse_car-enrichment_internal_dto.BodyType"
+                        }
+                    ]
+                },
+                "color": {
+                    "description": "Необязательные характеристики, null — значение неизвестно",
+                    "type": "string"
+                },
+                "engineVolume": {
+                    "description": "EngineVolume — объём двигателя в см³",
+                    "type": "integer"
+                },
+                "fuelType": {
+                    "enum": [
+                        "petrol",
+                        "diesel",
+                        "gas",
+                        "hybrid",
+                        "electric"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_jackvonhouse_car-enrichment_internal_dto.FuelType"
+                        }
+                    ]
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "mark": {
+                    "type": "string"
+                },
+                "markKnown": {
+                    "description": "MarkKnown и ModelKnown показывают, найдены ли марка и модель\nв справочнике, и не задаются клиентом",
+                    "type": "boolean"
+                },
+                "mileage": {
+                    "description": "Mileage — последний известный пробег в км на дату MileageAt",
+                    "type": "integer"
+                },
+                "mileageAt": {
+                    "type": "string"
+                },
+                "model": {
+                    "type": "string"
+                },
+                "modelKnown": {
+                    "type": "boolean"
+                },
+                "owner": {
+                    "$ref": "#/definitions/github_com_jackvonhouse_car-enrichment_internal_dto.Owner"
+                },
+                "power": {
+                    "description": "Power — мощность в л. с.",
+                    "type": "integer"
+                },
+                "regNum": {
+                    "type": "string"
+                },
+                "region": {
+                    "$ref": "#/definitions/github_com_jackvonhouse_car-enrichment_internal_dto.Region"
+                },
+                "vin": {
+                    "description": "VIN хранится в верхнем регистре, отсутствует у части автомобилей",
+                    "type": "string"
+                },
+                "vinInfo": {
+                    "$ref": "#/definitions/github_com_jackvonhouse_car-enrichment_internal_dto.VINInfo"
+                },
+                "year": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_jackvonhouse_car-enrichment_internal_dto.CarStats": {
+            "type": "object",
+            "properties": {
+                "byMark": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_jackvonhouse_car-enrichment_internal_dto.StatsBucket"
+                    }
+                },
+                "byModel": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_jackvonhouse_car-enrichment_internal_dto.StatsBucket"
+                    }
+                },
+                "byOwner": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_jackvonhouse_car-enrichment_internal_dto.StatsBucket"
+                    }
+                },
+                "byRegion": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_jackvonhouse_car-enrichment_internal_dto.StatsBucket"
+                    }
+                },
+                "byYear": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_jackvonhouse_car-enrichment_internal_dto.StatsBucket"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_jackvonhouse_car-enrichment_internal_dto.CreateAPIKey": {
+            "type": "object",
+            "properties": {
+                "expiresAt": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "enum": [
+                            "read",
+                            "write",
+                            "delete",
+                            "admin"
+                        ],
+                        "$ref": "#/definitions/github_com_jackvonhouse_car-enrichment_internal_identity.Scope"
+                    }
+                }
+            }
+        },
+        "github_com_jackvonhouse_car-enrichment_internal_dto.CreateAlias": {
+            "type": "object",
+            "properties": {
+                "alias": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_jackvonhouse_car-enrichment_internal_dto.CreateCar": {
+            "type": "object",
+            "properties": {
+                "reg_numbers": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "github_com_jackvonhouse_car-enrichment_internal_dto.CreateMark": {
+            "type": "object",
+            "properties": {
+                "aliases": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_jackvonhouse_car-enrichment_internal_dto.CreateModel": {
+            "type": "object",
+            "properties": {
+                "aliases": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_jackvonhouse_car-enrichment_internal_dto.FuelType": {
+            "type": "string",
+            "enum": [
+                "petrol",
+                "diesel",
+                "gas",
+                "hybrid",
+                "electric"
+            ],
+            "x-enum-varnames": [
+                "FuelTypePetrol",
+                "FuelTypeDiesel",
+                "FuelTypeGas",
+                "FuelTypeHybrid",
+                "FuelTypeElectric"
+            ]
+        },
+        "github_com_jackvonhouse_car-enrichment_internal_dto.ImportResult": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "integer"
+                },
+                "dryRun": {
+                    "type": "boolean"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_jackvonhouse_car-enrichment_internal_dto.ImportRowResult"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "valid": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_jackvonhouse_car-enrichment_internal_dto.ImportRowResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "line": {
+                    "type": "integer"
+                },
+                "regNum": {
+                    "type": "string"
+                },
+                "status": {
+                    "enum": [
+                        "created",
+                        "valid",
+                        "failed"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_jackvonhouse_car-enrichment_internal_dto.ImportStatus"
+                        }
+                    ]
+                }
+            }
+        },
+        "github_com_jackvonhouse_car-enrichment_internal_dto.ImportStatus": {
+            "type": "string",
+            "enum": [
+                "created",
+                "valid",
+                "failed"
+            ],
+            "x-enum-varnames": [
+                "ImportStatusCreated",
+                "ImportStatusValid",
+                "ImportStatusFailed"
+            ]
+        },
+        "github_com_jackvonhouse_car-enrichment_internal_dto.IssuedAPIKey": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "expiresAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "description": "Prefix — начало ключа, сам ключ после выдачи не хранится",
+                    "type": "string"
+                },
+                "revokedAt": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "enum": [
+                            "read",
+                            "write",
+                            "delete",
+                            "admin"
+                        ],
+                        "$ref": "#/definitions/github_com_jackvonhouse_car-enrichment_internal_identity.Scope"
+                    }
+                }
+            }
+        },
+        "github_com_jackvonhouse_car-enrichment_internal_dto.Mark": {
+            "type": "object",
+            "properties": {
+                "aliases": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_jackvonhouse_car-enrichment_internal_dto.Model": {
+            "type": "object",
+            "properties": {
+                "aliases": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "markId": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
         },
         "github_com_jackvonhouse_car-enrichment_internal_dto.Owner": {
             "type": "object",
@@ -366,6 +2347,125 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "github_com_jackvonhouse_car-enrichment_internal_dto.Region": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "subject": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_jackvonhouse_car-enrichment_internal_dto.SearchResult": {
+            "type": "object",
+            "properties": {
+                "car": {
+                    "$ref": "#/definitions/github_com_jackvonhouse_car-enrichment_internal_dto.Car"
+                },
+                "matched": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "rank": {
+                    "type": "number"
+                }
+            }
+        },
+        "github_com_jackvonhouse_car-enrichment_internal_dto.StatsBucket": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "key": {
+                    "description": "Key — значение группировки, \"unknown\" для неизвестных значений",
+                    "type": "string"
+                },
+                "label": {
+                    "description": "Label — расшифровка ключа: субъект РФ для региона, ФИО для владельца",
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_jackvonhouse_car-enrichment_internal_dto.VINInfo": {
+            "type": "object",
+            "properties": {
+                "checkDigitValid": {
+                    "type": "boolean"
+                },
+                "manufacturer": {
+                    "type": "string"
+                },
+                "modelYear": {
+                    "type": "integer"
+                },
+                "wmi": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_jackvonhouse_car-enrichment_internal_identity.Scope": {
+            "type": "string",
+            "enum": [
+                "read",
+                "write",
+                "delete",
+                "admin"
+            ],
+            "x-enum-varnames": [
+                "ScopeRead",
+                "ScopeWrite",
+                "ScopeDelete",
+                "ScopeAdmin"
+            ]
+        },
+        "github_com_jackvonhouse_car-enrichment_internal_transport.Page-github_com_jackvonhouse_car-enrichment_internal_dto_Car": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_jackvonhouse_car-enrichment_internal_dto.Car"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "next": {
+                    "type": "string"
+                },
+                "nextCursor": {
+                    "type": "string"
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "prev": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        }
+    },
+    "securityDefinitions": {
+        "ApiKeyAuth": {
+            "description": "Ключ API, выдаётся через /apikey",
+            "type": "apiKey",
+            "name": "X-API-Key",
+            "in": "header"
+        },
+        "BearerAuth": {
+            "description": "Токен пользователя JWT: \"Bearer \u003cтокен\u003e\"",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
         }
     }
 }`
@@ -0,0 +1,44 @@
+package dto
+
+import (
+	"github.com/jackvonhouse/car-enrichment/internal/identity"
+	"time"
+)
+
+type APIKey struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	// Prefix — начало ключа, сам ключ после выдачи не хранится
+	Prefix    string           `json:"prefix"`
+	Scopes    []identity.Scope `json:"scopes" enums:"read,write,delete,admin"`
+	CreatedAt time.Time        `json:"createdAt"`
+	ExpiresAt *time.Time       `json:"expiresAt"`
+	RevokedAt *time.Time       `json:"revokedAt"`
+}
+
+// Active сообщает, принимается ли ключ в момент now
+func (k APIKey) Active(
+	now time.Time,
+) bool {
+
+	if k.RevokedAt != nil {
+		return false
+	}
+
+	return k.ExpiresAt == nil || now.Before(*k.ExpiresAt)
+}
+
+type CreateAPIKey struct {
+	Name      string           `json:"name"`
+	Scopes    []identity.Scope `json:"scopes" enums:"read,write,delete,admin"`
+	ExpiresAt *time.Time       `json:"expiresAt"`
+
+	Prefix string `json:"-"`
+	Hash   string `json:"-"`
+}
+
+// IssuedAPIKey возвращается один раз при выдаче, только в нём есть сам ключ
+type IssuedAPIKey struct {
+	APIKey
+	Key string `json:"key"`
+}
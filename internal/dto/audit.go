@@ -0,0 +1,48 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	AuditEntityCar    = "car"
+	AuditEntityOwner  = "owner"
+	AuditEntityAPIKey = "api_key"
+)
+
+const (
+	AuditActionCreate = "create"
+	AuditActionUpdate = "update"
+	AuditActionDelete = "delete"
+	AuditActionRevoke = "revoke"
+	// AuditActionTransfer — автомобиль передан другому владельцу
+	AuditActionTransfer = "transfer"
+)
+
+type Audit struct {
+	ID        int64           `json:"id"`
+	Actor     string          `json:"actor"`
+	CreatedAt time.Time       `json:"createdAt"`
+	Entity    string          `json:"entity"`
+	EntityID  int64           `json:"entityId"`
+	Action    string          `json:"action"`
+	Before    json.RawMessage `json:"before" swaggertype:"object"`
+	After     json.RawMessage `json:"after" swaggertype:"object"`
+}
+
+type CreateAudit struct {
+	Entity   string
+	EntityID int64
+	Action   string
+	Before   any
+	After    any
+}
+
+type AuditFilter struct {
+	Entity   string
+	EntityID int64
+	Actor    string
+	From     time.Time
+	To       time.Time
+}
@@ -1,12 +1,90 @@
 package dto
 
+import (
+	"slices"
+	"time"
+)
+
 type Car struct {
 	ID     int64  `json:"id"`
 	RegNum string `json:"regNum"`
 	Mark   string `json:"mark"`
 	Model  string `json:"model"`
-	Year   int    `json:"year"`
-	Owner  Owner  `json:"owner"`
+	// MarkKnown и ModelKnown показывают, найдены ли марка и модель
+	// в справочнике, и не задаются клиентом
+	MarkKnown  bool    `json:"markKnown"`
+	ModelKnown bool    `json:"modelKnown"`
+	MarkID     *int64  `json:"-"`
+	ModelID    *int64  `json:"-"`
+	Year       int     `json:"year"`
+	Region     *Region `json:"region"`
+	// Необязательные характеристики, null — значение неизвестно
+	Color    *string   `json:"color"`
+	BodyType *BodyType `json:"bodyType" enums:"sedan,hatchback,liftback,wagon,suv,coupe,convertible,minivan,pickup,van"`
+	FuelType *FuelType `json:"fuelType" enums:"petrol,diesel,gas,hybrid,electric"`
+	// EngineVolume — объём двигателя в см³
+	EngineVolume *int `json:"engineVolume"`
+	// Power — мощность в л. с.
+	Power *int `json:"power"`
+	// Mileage — последний известный пробег в км на дату MileageAt
+	Mileage   *int       `json:"mileage"`
+	MileageAt *time.Time `json:"mileageAt"`
+	// VIN хранится в верхнем регистре, отсутствует у части автомобилей
+	VIN     *string  `json:"vin"`
+	VINInfo *VINInfo `json:"vinInfo"`
+	Owner   Owner    `json:"owner"`
+}
+
+// Region определяется по гос. номеру и не задаётся клиентом
+type Region struct {
+	Code    string `json:"code"`
+	Subject string `json:"subject,omitempty"`
+}
+
+type BodyType string
+
+const (
+	BodyTypeSedan       BodyType = "sedan"
+	BodyTypeHatchback   BodyType = "hatchback"
+	BodyTypeLiftback    BodyType = "liftback"
+	BodyTypeWagon       BodyType = "wagon"
+	BodyTypeSUV         BodyType = "suv"
+	BodyTypeCoupe       BodyType = "coupe"
+	BodyTypeConvertible BodyType = "convertible"
+	BodyTypeMinivan     BodyType = "minivan"
+	BodyTypePickup      BodyType = "pickup"
+	BodyTypeVan         BodyType = "van"
+)
+
+var BodyTypes = []BodyType{
+	BodyTypeSedan, BodyTypeHatchback, BodyTypeLiftback, BodyTypeWagon, BodyTypeSUV,
+	BodyTypeCoupe, BodyTypeConvertible, BodyTypeMinivan, BodyTypePickup, BodyTypeVan,
+}
+
+func (b BodyType) Valid() bool { return slices.Contains(BodyTypes, b) }
+
+type FuelType string
+
+const (
+	FuelTypePetrol   FuelType = "petrol"
+	FuelTypeDiesel   FuelType = "diesel"
+	FuelTypeGas      FuelType = "gas"
+	FuelTypeHybrid   FuelType = "hybrid"
+	FuelTypeElectric FuelType = "electric"
+)
+
+var FuelTypes = []FuelType{
+	FuelTypePetrol, FuelTypeDiesel, FuelTypeGas, FuelTypeHybrid, FuelTypeElectric,
+}
+
+func (f FuelType) Valid() bool { return slices.Contains(FuelTypes, f) }
+
+// VINInfo расшифровывается из VIN и не задаётся клиентом
+type VINInfo struct {
+	WMI             string `json:"wmi"`
+	Manufacturer    string `json:"manufacturer,omitempty"`
+	ModelYear       int    `json:"modelYear,omitempty"`
+	CheckDigitValid bool   `json:"checkDigitValid"`
 }
 
 type EnrichmentCar struct {
@@ -18,17 +96,65 @@ type CreateCar struct {
 	RegNumbers []string `json:"reg_numbers"`
 }
 
+type CarList struct {
+	Items []Car
+	// Total заполняется только при Pagination.WithTotal
+	Total int64
+	// NextCursor пуст, если следующей страницы нет
+	NextCursor string
+}
+
+type Sort struct {
+	Field string
+	Desc  bool
+}
+
 type Pagination struct {
 	Offset int
 	Limit  int
+	// Cursor — непрозрачный курсор предыдущей страницы,
+	// при его наличии Offset не используется
+	Cursor string
+	Sort   []Sort
+
+	WithTotal bool
+}
+
+type Operator string
+
+const (
+	OperatorEq      Operator = "eq"
+	OperatorNe      Operator = "ne"
+	OperatorIn      Operator = "in"
+	OperatorLike    Operator = "like"
+	OperatorPrefix  Operator = "prefix"
+	OperatorGte     Operator = "gte"
+	OperatorLte     Operator = "lte"
+	OperatorBetween Operator = "between"
+	OperatorSimilar Operator = "similar"
+)
+
+type Condition struct {
+	Operator Operator
+	Values   []string
 }
 
+// Filter содержит условия по каждому полю, условия объединяются через AND
 type Filter struct {
-	RegNum          string
-	Mark            string
-	Model           string
-	Year            int
-	OwnerName       string
-	OwnerSurname    string
-	OwnerPatronymic string
+	RegNum          []Condition
+	Mark            []Condition
+	Model           []Condition
+	Year            []Condition
+	Region          []Condition
+	VIN             []Condition
+	Color           []Condition
+	BodyType        []Condition
+	FuelType        []Condition
+	EngineVolume    []Condition
+	Power           []Condition
+	Mileage         []Condition
+	MileageAt       []Condition
+	OwnerName       []Condition
+	OwnerSurname    []Condition
+	OwnerPatronymic []Condition
 }
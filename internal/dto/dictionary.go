@@ -0,0 +1,38 @@
+package dto
+
+type Mark struct {
+	ID      int64    `json:"id"`
+	Name    string   `json:"name"`
+	Aliases []string `json:"aliases"`
+}
+
+type CreateMark struct {
+	Name    string   `json:"name"`
+	Aliases []string `json:"aliases"`
+}
+
+type Model struct {
+	ID      int64    `json:"id"`
+	MarkID  int64    `json:"markId"`
+	Name    string   `json:"name"`
+	Aliases []string `json:"aliases"`
+}
+
+type CreateModel struct {
+	MarkID  int64    `json:"-"`
+	Name    string   `json:"name"`
+	Aliases []string `json:"aliases"`
+}
+
+type CreateAlias struct {
+	Alias string `json:"alias"`
+}
+
+// Normalized — марка и модель после сопоставления со справочником.
+// Ненайденные значения остаются в исходном виде с пустым идентификатором
+type Normalized struct {
+	MarkID  *int64
+	Mark    string
+	ModelID *int64
+	Model   string
+}
@@ -0,0 +1,28 @@
+package dto
+
+type HealthStatus string
+
+const (
+	HealthStatusOk   HealthStatus = "ok"
+	HealthStatusFail HealthStatus = "fail"
+)
+
+type HealthCheck struct {
+	Status HealthStatus `json:"status"`
+	Error  string       `json:"error,omitempty"`
+	// LatencyMs — время проверки в миллисекундах
+	LatencyMs int64          `json:"latencyMs"`
+	Details   map[string]any `json:"details,omitempty"`
+}
+
+type Health struct {
+	Status HealthStatus           `json:"status"`
+	Checks map[string]HealthCheck `json:"checks,omitempty"`
+}
+
+// Migration — состояние миграций из таблицы schema_migrations
+type Migration struct {
+	Version uint
+	// Dirty означает, что миграция прервалась и база требует ручного исправления
+	Dirty bool
+}
@@ -0,0 +1,44 @@
+package dto
+
+// ImportRow — строка загружаемого файла после разбора
+type ImportRow struct {
+	// Line — номер строки в файле, считая заголовок
+	Line int
+	Car  Car
+	// PlateOnly — в строке только гос. номер, остальное даёт обогащение
+	PlateOnly bool
+	// Error — ошибка разбора, такая строка не импортируется
+	Error string
+}
+
+type ImportOptions struct {
+	// Enrich — обогащать строки, в которых только гос. номер
+	Enrich bool
+	// DryRun — только проверить строки, ничего не записывая
+	DryRun bool
+}
+
+type ImportStatus string
+
+const (
+	ImportStatusCreated ImportStatus = "created"
+	// ImportStatusValid — строка прошла проверку в режиме dryRun
+	ImportStatusValid  ImportStatus = "valid"
+	ImportStatusFailed ImportStatus = "failed"
+)
+
+type ImportRowResult struct {
+	Line   int          `json:"line"`
+	RegNum string       `json:"regNum"`
+	Status ImportStatus `json:"status" enums:"created,valid,failed"`
+	Error  string       `json:"error,omitempty"`
+}
+
+type ImportResult struct {
+	DryRun  bool              `json:"dryRun"`
+	Total   int               `json:"total"`
+	Created int               `json:"created"`
+	Valid   int               `json:"valid"`
+	Failed  int               `json:"failed"`
+	Rows    []ImportRowResult `json:"rows"`
+}
@@ -0,0 +1,13 @@
+package dto
+
+import "time"
+
+// QuotaReset — начало следующих суток по UTC, когда обнуляются суточные квоты
+func QuotaReset(
+	now time.Time,
+) time.Time {
+
+	year, month, day := now.UTC().Date()
+
+	return time.Date(year, month, day+1, 0, 0, 0, 0, time.UTC)
+}
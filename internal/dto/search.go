@@ -0,0 +1,7 @@
+package dto
+
+type SearchResult struct {
+	Car     Car      `json:"car"`
+	Rank    float64  `json:"rank"`
+	Matched []string `json:"matched"`
+}
@@ -0,0 +1,26 @@
+package dto
+
+// StatsBucket — количество автомобилей в одной группе
+type StatsBucket struct {
+	// Key — значение группировки, "unknown" для неизвестных значений
+	Key string `json:"key"`
+	// Label — расшифровка ключа: субъект РФ для региона, ФИО для владельца
+	Label string `json:"label,omitempty"`
+	Count int64  `json:"count"`
+}
+
+type CarStats struct {
+	Total    int64         `json:"total"`
+	ByMark   []StatsBucket `json:"byMark"`
+	ByModel  []StatsBucket `json:"byModel"`
+	ByYear   []StatsBucket `json:"byYear"`
+	ByRegion []StatsBucket `json:"byRegion"`
+	ByOwner  []StatsBucket `json:"byOwner"`
+}
+
+type StatsOptions struct {
+	// YearBucket — ширина интервала годов выпуска
+	YearBucket int
+	// Top ограничивает число групп по марке, модели, региону и владельцу
+	Top int
+}
@@ -8,4 +8,7 @@ var (
 	ErrNotFound      = errors.NewType("not found")
 	ErrAlreadyExists = errors.NewType("already exists")
 	ErrFailed        = errors.NewType("failed")
+	ErrUnauthorized  = errors.NewType("unauthorized")
+	ErrForbidden     = errors.NewType("forbidden")
+	ErrLimitExceeded = errors.NewType("limit exceeded")
 )
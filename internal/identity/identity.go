@@ -0,0 +1,78 @@
+package identity
+
+import (
+	"context"
+	"slices"
+)
+
+const Anonymous = "anonymous"
+
+// Scope — право клиента на группу операций
+type Scope string
+
+const (
+	ScopeRead   Scope = "read"
+	ScopeWrite  Scope = "write"
+	ScopeDelete Scope = "delete"
+	// ScopeAdmin включает все остальные права и управление ключами
+	ScopeAdmin Scope = "admin"
+)
+
+var Scopes = []Scope{ScopeRead, ScopeWrite, ScopeDelete, ScopeAdmin}
+
+func (s Scope) Valid() bool {
+	return slices.Contains(Scopes, s)
+}
+
+// Role — роль пользователя из токена, раскрывается в набор прав
+type Role string
+
+const (
+	RoleViewer Role = "viewer"
+	RoleEditor Role = "editor"
+	RoleAdmin  Role = "admin"
+)
+
+var RoleScopes = map[Role][]Scope{
+	RoleViewer: {ScopeRead},
+	RoleEditor: {ScopeRead, ScopeWrite},
+	RoleAdmin:  {ScopeAdmin},
+}
+
+type Identity struct {
+	Name   string
+	Scopes []Scope
+	// Client — ключ клиента для лимитов и квот: имя ключа или
+	// пользователя, а без проверки подлинности — IP-адрес,
+	// так как имя из X-Actor задаёт сам клиент
+	Client string
+}
+
+func (i Identity) Has(
+	scope Scope,
+) bool {
+
+	return slices.Contains(i.Scopes, scope) || slices.Contains(i.Scopes, ScopeAdmin)
+}
+
+type contextKey struct{}
+
+func With(
+	ctx context.Context,
+	identity Identity,
+) context.Context {
+
+	return context.WithValue(ctx, contextKey{}, identity)
+}
+
+func FromContext(
+	ctx context.Context,
+) Identity {
+
+	identity, ok := ctx.Value(contextKey{}).(Identity)
+	if !ok || identity.Name == "" {
+		identity.Name = Anonymous
+	}
+
+	return identity
+}
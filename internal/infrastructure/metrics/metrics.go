@@ -0,0 +1,176 @@
+package metrics
+
+import (
+	"database/sql"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const namespace = "car_enrichment"
+
+const (
+	OutcomeSuccess = "success"
+	OutcomeError   = "error"
+
+	ResultEnriched = "enriched"
+	ResultFailed   = "failed"
+)
+
+// Metrics — метрики сервиса в формате Prometheus. Слои зависят
+// не от него, а от своих небольших интерфейсов
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpRequests *prometheus.HistogramVec
+
+	enrichmentAttempts *prometheus.CounterVec
+	enrichmentDuration *prometheus.HistogramVec
+	enrichmentRetries  *prometheus.CounterVec
+	enrichmentResults  *prometheus.CounterVec
+
+	carsCreated prometheus.Counter
+	carsUpdated prometheus.Counter
+}
+
+func New(
+	db *sql.DB,
+) *Metrics {
+
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+
+		httpRequests: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "Duration of HTTP requests by route template and status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+
+		enrichmentAttempts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "enrichment",
+			Name:      "attempts_total",
+			Help:      "Requests to the enrichment provider by outcome.",
+		}, []string{"provider", "outcome"}),
+
+		enrichmentDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "enrichment",
+			Name:      "attempt_duration_seconds",
+			Help:      "Duration of requests to the enrichment provider.",
+			Buckets:   []float64{.01, .025, .05, .1, .25, .5, 1, 2.5},
+		}, []string{"provider", "outcome"}),
+
+		enrichmentRetries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "enrichment",
+			Name:      "retries_total",
+			Help:      "Repeated requests to the enrichment provider after a failure.",
+		}, []string{"provider"}),
+
+		enrichmentResults: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "enrichment",
+			Name:      "results_total",
+			Help:      "Registration numbers enriched or failed after all attempts.",
+		}, []string{"provider", "result"}),
+
+		carsCreated: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "cars",
+			Name:      "created_total",
+			Help:      "Cars created.",
+		}),
+
+		carsUpdated: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "cars",
+			Name:      "updated_total",
+			Help:      "Cars updated.",
+		}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		collectors.NewDBStatsCollector(db, "postgres"),
+
+		m.httpRequests,
+		m.enrichmentAttempts,
+		m.enrichmentDuration,
+		m.enrichmentRetries,
+		m.enrichmentResults,
+		m.carsCreated,
+		m.carsUpdated,
+	)
+
+	return m
+}
+
+// Handler отдаёт метрики в текстовом формате Prometheus
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+func (m *Metrics) ObserveRequest(
+	method string,
+	route string,
+	status int,
+	duration time.Duration,
+) {
+
+	m.httpRequests.
+		WithLabelValues(method, route, strconv.Itoa(status)).
+		Observe(duration.Seconds())
+}
+
+func (m *Metrics) EnrichmentAttempt(
+	provider string,
+	err error,
+	duration time.Duration,
+) {
+
+	outcome := OutcomeSuccess
+	if err != nil {
+		outcome = OutcomeError
+	}
+
+	m.enrichmentAttempts.WithLabelValues(provider, outcome).Inc()
+	m.enrichmentDuration.WithLabelValues(provider, outcome).Observe(duration.Seconds())
+}
+
+func (m *Metrics) EnrichmentRetry(
+	provider string,
+) {
+
+	m.enrichmentRetries.WithLabelValues(provider).Inc()
+}
+
+func (m *Metrics) EnrichmentResult(
+	provider string,
+	enriched bool,
+) {
+
+	result := ResultEnriched
+	if !enriched {
+		result = ResultFailed
+	}
+
+	m.enrichmentResults.WithLabelValues(provider, result).Inc()
+}
+
+func (m *Metrics) CarsCreated(
+	count int,
+) {
+
+	m.carsCreated.Add(float64(count))
+}
+
+func (m *Metrics) CarUpdated() {
+	m.carsUpdated.Inc()
+}
@@ -32,3 +32,10 @@ func New(
 }
 
 func (d Database) Database() *sqlx.DB { return d.db }
+
+func (d Database) Ping(
+	ctx context.Context,
+) error {
+
+	return d.db.PingContext(ctx)
+}
@@ -0,0 +1,47 @@
+package postgres
+
+import (
+	"context"
+	"github.com/jackvonhouse/car-enrichment/internal/errors"
+	"github.com/jackvonhouse/car-enrichment/pkg/log"
+	"github.com/jmoiron/sqlx"
+)
+
+// Transaction выполняет fn в транзакции: откатывает её, если fn
+// вернула ошибку, и фиксирует иначе. Ошибка fn возвращается как есть,
+// ошибки самой транзакции — как ErrInternal "can't <operation>"
+func Transaction(
+	ctx context.Context,
+	db *sqlx.DB,
+	operation string,
+	logger log.Logger,
+	fn func(*sqlx.Tx) error,
+) error {
+
+	logger.WithContext(ctx).Infof("starting %s transaction", operation)
+
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		logger.WithContext(ctx).Warnf("can't start transaction: %s", err)
+
+		return errors.ErrInternal.New("can't " + operation).Wrap(err)
+	}
+
+	if err := fn(tx); err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			logger.WithContext(ctx).Warnf("unknown error on rollback: %s", rollbackErr)
+
+			return errors.ErrInternal.New("can't " + operation).Wrap(rollbackErr)
+		}
+
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		logger.WithContext(ctx).Warnf("unknown error on commit: %s", err)
+
+		return errors.ErrInternal.New("can't " + operation).Wrap(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,282 @@
+package apikey
+
+import (
+	"context"
+	"database/sql"
+	sq "github.com/Masterminds/squirrel"
+	pgerr "github.com/jackc/pgerrcode"
+	"github.com/jackvonhouse/car-enrichment/internal/dto"
+	"github.com/jackvonhouse/car-enrichment/internal/errors"
+	"github.com/jackvonhouse/car-enrichment/internal/identity"
+	"github.com/jackvonhouse/car-enrichment/internal/infrastructure/postgres"
+	errpkg "github.com/jackvonhouse/car-enrichment/pkg/errors"
+	"github.com/jackvonhouse/car-enrichment/pkg/log"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"strings"
+	"time"
+)
+
+type auditRepository interface {
+	Write(context.Context, *sqlx.Tx, ...dto.CreateAudit) error
+}
+
+type Repository struct {
+	db *sqlx.DB
+
+	audit auditRepository
+
+	logger log.Logger
+}
+
+func New(
+	db *sqlx.DB,
+	audit auditRepository,
+	logger log.Logger,
+) Repository {
+
+	return Repository{
+		db:     db,
+		audit:  audit,
+		logger: logger.WithField("unit", "api key"),
+	}
+}
+
+type row struct {
+	ID        int64          `db:"id"`
+	Name      string         `db:"name"`
+	Prefix    string         `db:"prefix"`
+	Scopes    pq.StringArray `db:"scopes"`
+	CreatedAt time.Time      `db:"created_at"`
+	ExpiresAt sql.NullTime   `db:"expires_at"`
+	RevokedAt sql.NullTime   `db:"revoked_at"`
+}
+
+func (r row) key() dto.APIKey {
+	key := dto.APIKey{
+		ID:        r.ID,
+		Name:      r.Name,
+		Prefix:    r.Prefix,
+		Scopes:    make([]identity.Scope, len(r.Scopes)),
+		CreatedAt: r.CreatedAt,
+	}
+
+	for i, scope := range r.Scopes {
+		key.Scopes[i] = identity.Scope(scope)
+	}
+
+	if r.ExpiresAt.Valid {
+		key.ExpiresAt = &r.ExpiresAt.Time
+	}
+
+	if r.RevokedAt.Valid {
+		key.RevokedAt = &r.RevokedAt.Time
+	}
+
+	return key
+}
+
+var columns = []string{"id", "name", "prefix", "scopes", "created_at", "expires_at", "revoked_at"}
+
+func (r Repository) Create(
+	ctx context.Context,
+	create dto.CreateAPIKey,
+) (dto.APIKey, error) {
+
+	scopes := make(pq.StringArray, len(create.Scopes))
+	for i, scope := range create.Scopes {
+		scopes[i] = string(scope)
+	}
+
+	query, args, err := sq.
+		Insert("api_key").
+		Columns("name", "prefix", "hash", "scopes", "expires_at").
+		Values(create.Name, create.Prefix, create.Hash, scopes, create.ExpiresAt).
+		Suffix("RETURNING " + sqlColumns()).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+
+	// Хэш ключа в журнал не пишется
+	logger := r.logger.WithContext(ctx).WithFields(map[string]any{
+		"query": query,
+		"args": map[string]any{
+			"name":      create.Name,
+			"scopes":    create.Scopes,
+			"expiresAt": create.ExpiresAt,
+		},
+	})
+
+	if err != nil {
+		logger.Warnf("error on create sql query: %s", err)
+
+		return dto.APIKey{}, errors.ErrInternal.New("can't create api key").Wrap(err)
+	}
+
+	var key dto.APIKey
+
+	err = postgres.Transaction(ctx, r.db, "create api key", r.logger, func(tx *sqlx.Tx) error {
+		created := row{}
+
+		if err := tx.GetContext(ctx, &created, query, args...); err != nil {
+			if e, ok := err.(*pq.Error); ok && e.Code == pgerr.UniqueViolation {
+				logger.Warnf("api key already exists: %s", err)
+
+				return errors.ErrAlreadyExists.New("api key with this name already exists").Wrap(err)
+			}
+
+			logger.Warnf("can't create api key: %s", err)
+
+			return errors.ErrInternal.New("can't create api key").Wrap(err)
+		}
+
+		key = created.key()
+
+		return r.audit.Write(ctx, tx, dto.CreateAudit{
+			Entity:   dto.AuditEntityAPIKey,
+			EntityID: key.ID,
+			Action:   dto.AuditActionCreate,
+			After:    key,
+		})
+	})
+
+	if err != nil {
+		return dto.APIKey{}, err
+	}
+
+	return key, nil
+}
+
+func (r Repository) Get(
+	ctx context.Context,
+) ([]dto.APIKey, error) {
+
+	query, args, err := sq.
+		Select(columns...).
+		From("api_key").
+		OrderBy("id").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+
+	logger := r.logger.WithContext(ctx).WithField("query", query)
+
+	if err != nil {
+		logger.Warnf("can't get api keys: %s", err)
+
+		return []dto.APIKey{}, errors.ErrInternal.New("can't get api keys").Wrap(err)
+	}
+
+	rows := make([]row, 0)
+
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		logger.Warnf("can't get api keys: %s", err)
+
+		return []dto.APIKey{}, errors.ErrInternal.New("can't get api keys").Wrap(err)
+	}
+
+	keys := make([]dto.APIKey, len(rows))
+	for i, raw := range rows {
+		keys[i] = raw.key()
+	}
+
+	return keys, nil
+}
+
+// GetByHash ищет ключ по хэшу, в том числе отозванный
+// или истёкший, проверка остаётся вызывающему
+func (r Repository) GetByHash(
+	ctx context.Context,
+	hash string,
+) (dto.APIKey, error) {
+
+	query, args, err := sq.
+		Select(columns...).
+		From("api_key").
+		Where(sq.Eq{"hash": hash}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+
+	logger := r.logger.WithContext(ctx).WithField("query", query)
+
+	if err != nil {
+		logger.Warnf("can't get api key: %s", err)
+
+		return dto.APIKey{}, errors.ErrInternal.New("can't get api key").Wrap(err)
+	}
+
+	raw := row{}
+
+	if err := r.db.GetContext(ctx, &raw, query, args...); err != nil {
+		if errpkg.Is(err, sql.ErrNoRows) {
+			return dto.APIKey{}, errors.ErrNotFound.New("api key not found").Wrap(err)
+		}
+
+		logger.Warnf("can't get api key: %s", err)
+
+		return dto.APIKey{}, errors.ErrInternal.New("can't get api key").Wrap(err)
+	}
+
+	return raw.key(), nil
+}
+
+// Revoke отзывает ключ. Запись остаётся, чтобы аудит
+// по имени ключа оставался понятным
+func (r Repository) Revoke(
+	ctx context.Context,
+	keyId int64,
+) error {
+
+	query, args, err := sq.
+		Update("api_key").
+		Set("revoked_at", sq.Expr("now()")).
+		Where(sq.Eq{"id": keyId}).
+		Where(sq.Eq{"revoked_at": nil}).
+		Suffix("RETURNING " + sqlColumns()).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+
+	logger := r.logger.WithContext(ctx).WithFields(map[string]any{
+		"query": query,
+		"args": map[string]any{
+			"id": keyId,
+		},
+	})
+
+	if err != nil {
+		logger.Warnf("error on revoke sql query: %s", err)
+
+		return errors.ErrInternal.New("can't revoke api key").Wrap(err)
+	}
+
+	return postgres.Transaction(ctx, r.db, "revoke api key", r.logger, func(tx *sqlx.Tx) error {
+		revoked := row{}
+
+		if err := tx.GetContext(ctx, &revoked, query, args...); err != nil {
+			if errpkg.Is(err, sql.ErrNoRows) {
+				logger.Warnf("api key not found: %s", err)
+
+				return errors.ErrNotFound.New("api key not found or already revoked").Wrap(err)
+			}
+
+			logger.Warnf("can't revoke api key: %s", err)
+
+			return errors.ErrInternal.New("can't revoke api key").Wrap(err)
+		}
+
+		after := revoked.key()
+
+		before := after
+		before.RevokedAt = nil
+
+		return r.audit.Write(ctx, tx, dto.CreateAudit{
+			Entity:   dto.AuditEntityAPIKey,
+			EntityID: after.ID,
+			Action:   dto.AuditActionRevoke,
+			Before:   before,
+			After:    after,
+		})
+	})
+}
+
+func sqlColumns() string {
+	return strings.Join(columns, ", ")
+}
@@ -0,0 +1,225 @@
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackvonhouse/car-enrichment/internal/dto"
+	"github.com/jackvonhouse/car-enrichment/internal/errors"
+	"github.com/jackvonhouse/car-enrichment/internal/identity"
+	errpkg "github.com/jackvonhouse/car-enrichment/pkg/errors"
+	"github.com/jackvonhouse/car-enrichment/pkg/log"
+	"github.com/jmoiron/sqlx"
+	"time"
+)
+
+type Repository struct {
+	db *sqlx.DB
+
+	logger log.Logger
+}
+
+func New(
+	db *sqlx.DB,
+	logger log.Logger,
+) Repository {
+
+	return Repository{
+		db:     db,
+		logger: logger.WithField("unit", "audit"),
+	}
+}
+
+// Write добавляет записи аудита в рамках транзакции изменения,
+// чтобы запись не появлялась без самого изменения и наоборот.
+func (r Repository) Write(
+	ctx context.Context,
+	tx *sqlx.Tx,
+	records ...dto.CreateAudit,
+) error {
+
+	if len(records) == 0 {
+		return nil
+	}
+
+	actor := identity.FromContext(ctx).Name
+
+	insertBuilder := sq.
+		Insert("audit").
+		Columns("actor", "entity", "entity_id", "action", "before", "after")
+
+	for _, record := range records {
+		before, err := r.marshal(record.Before)
+		if err != nil {
+			r.logger.WithContext(ctx).Warnf("can't marshal audit state: %s", err)
+
+			return errors.ErrInternal.New("can't write audit").Wrap(err)
+		}
+
+		after, err := r.marshal(record.After)
+		if err != nil {
+			r.logger.WithContext(ctx).Warnf("can't marshal audit state: %s", err)
+
+			return errors.ErrInternal.New("can't write audit").Wrap(err)
+		}
+
+		insertBuilder = insertBuilder.Values(
+			actor, record.Entity, record.EntityID, record.Action, before, after,
+		)
+	}
+
+	query, args, err := insertBuilder.PlaceholderFormat(sq.Dollar).ToSql()
+
+	logger := r.logger.WithContext(ctx).WithFields(map[string]any{
+		"request": map[string]any{
+			"query": query,
+			"args": map[string]any{
+				"actor":   actor,
+				"records": len(records),
+			},
+		},
+	})
+
+	if err != nil {
+		logger.Warnf("error on create sql query: %s", err)
+
+		return errors.ErrInternal.New("can't write audit").Wrap(err)
+	}
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		logger.Warnf("can't write audit: %s", err)
+
+		return errors.ErrInternal.New("can't write audit").Wrap(err)
+	}
+
+	return nil
+}
+
+func (r Repository) marshal(
+	state any,
+) (any, error) {
+
+	if state == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(data), nil
+}
+
+func (r Repository) Get(
+	ctx context.Context,
+	filter dto.AuditFilter,
+	pagination dto.Pagination,
+) ([]dto.Audit, error) {
+
+	selectBuilder := sq.
+		Select(
+			"id", "actor", "created_at", "entity",
+			"entity_id", "action", "before", "after",
+		).
+		From("audit").
+		OrderBy("id DESC").
+		Offset(uint64(pagination.Offset)).
+		Limit(uint64(pagination.Limit)).
+		PlaceholderFormat(sq.Dollar)
+
+	selectBuilder = r.where(selectBuilder, filter)
+
+	query, args, err := selectBuilder.ToSql()
+
+	logger := r.logger.WithContext(ctx).WithFields(map[string]any{
+		"query": query,
+		"args": map[string]any{
+			"filter": filter,
+			"limit":  pagination.Limit,
+			"offset": pagination.Offset,
+		},
+	})
+
+	if err != nil {
+		logger.Warnf("can't get audit: %s", err)
+
+		return []dto.Audit{}, errors.ErrInternal.New("can't get audit").Wrap(err)
+	}
+
+	type audit struct {
+		ID        int64          `db:"id"`
+		Actor     string         `db:"actor"`
+		CreatedAt time.Time      `db:"created_at"`
+		Entity    string         `db:"entity"`
+		EntityID  int64          `db:"entity_id"`
+		Action    string         `db:"action"`
+		Before    sql.NullString `db:"before"`
+		After     sql.NullString `db:"after"`
+	}
+
+	rawRecords := make([]audit, 0)
+
+	if err := r.db.SelectContext(ctx, &rawRecords, query, args...); err != nil {
+		if !errpkg.Is(err, sql.ErrNoRows) {
+			logger.Warnf("can't get audit: %s", err)
+
+			return []dto.Audit{}, errors.ErrInternal.New("can't get audit").Wrap(err)
+		}
+
+		logger.Warnf("no audit records: %s", err)
+
+		return []dto.Audit{}, errors.ErrNotFound.New("no audit records").Wrap(err)
+	}
+
+	records := make([]dto.Audit, len(rawRecords))
+	for i, rawRecord := range rawRecords {
+		records[i] = dto.Audit{
+			ID:        rawRecord.ID,
+			Actor:     rawRecord.Actor,
+			CreatedAt: rawRecord.CreatedAt,
+			Entity:    rawRecord.Entity,
+			EntityID:  rawRecord.EntityID,
+			Action:    rawRecord.Action,
+		}
+
+		if rawRecord.Before.Valid {
+			records[i].Before = json.RawMessage(rawRecord.Before.String)
+		}
+
+		if rawRecord.After.Valid {
+			records[i].After = json.RawMessage(rawRecord.After.String)
+		}
+	}
+
+	return records, nil
+}
+
+func (r Repository) where(
+	builder sq.SelectBuilder,
+	filter dto.AuditFilter,
+) sq.SelectBuilder {
+
+	if filter.Entity != "" {
+		builder = builder.Where(sq.Eq{"entity": filter.Entity})
+	}
+
+	if filter.EntityID != 0 {
+		builder = builder.Where(sq.Eq{"entity_id": filter.EntityID})
+	}
+
+	if filter.Actor != "" {
+		builder = builder.Where(sq.Eq{"actor": filter.Actor})
+	}
+
+	if !filter.From.IsZero() {
+		builder = builder.Where(sq.GtOrEq{"created_at": filter.From})
+	}
+
+	if !filter.To.IsZero() {
+		builder = builder.Where(sq.Lt{"created_at": filter.To})
+	}
+
+	return builder
+}
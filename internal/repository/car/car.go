@@ -5,50 +5,219 @@ import (
 	"database/sql"
 	sq "github.com/Masterminds/squirrel"
 	pgerr "github.com/jackc/pgerrcode"
+	"github.com/jackvonhouse/car-enrichment/config"
 	"github.com/jackvonhouse/car-enrichment/internal/dto"
 	"github.com/jackvonhouse/car-enrichment/internal/errors"
+	"github.com/jackvonhouse/car-enrichment/internal/infrastructure/postgres"
 	errpkg "github.com/jackvonhouse/car-enrichment/pkg/errors"
 	"github.com/jackvonhouse/car-enrichment/pkg/log"
+	"github.com/jackvonhouse/car-enrichment/pkg/plate"
+	"github.com/jackvonhouse/car-enrichment/pkg/vin"
 	"github.com/jmoiron/sqlx"
 	"github.com/lib/pq"
 	"strings"
 )
 
+type auditRepository interface {
+	Write(context.Context, *sqlx.Tx, ...dto.CreateAudit) error
+}
+
 type Repository struct {
 	db *sqlx.DB
 
+	audit auditRepository
+
+	config config.Search
 	logger log.Logger
 }
 
 func New(
 	db *sqlx.DB,
+	audit auditRepository,
+	config config.Search,
 	logger log.Logger,
 ) Repository {
 
 	return Repository{
 		db:     db,
+		audit:  audit,
+		config: config,
 		logger: logger.WithField("unit", "car"),
 	}
 }
 
+type row struct {
+	CarID           int64          `db:"car_id"`
+	RegNum          string         `db:"car_regnum"`
+	Mark            string         `db:"car_mark"`
+	Model           string         `db:"car_model"`
+	MarkID          sql.NullInt64  `db:"car_mark_id"`
+	ModelID         sql.NullInt64  `db:"car_model_id"`
+	Year            int            `db:"car_year"`
+	Region          sql.NullString `db:"car_region"`
+	VIN             sql.NullString `db:"car_vin"`
+	Color           sql.NullString `db:"car_color"`
+	BodyType        sql.NullString `db:"car_body_type"`
+	FuelType        sql.NullString `db:"car_fuel_type"`
+	EngineVolume    sql.NullInt64  `db:"car_engine_volume"`
+	Power           sql.NullInt64  `db:"car_power"`
+	Mileage         sql.NullInt64  `db:"car_mileage"`
+	MileageAt       sql.NullTime   `db:"car_mileage_at"`
+	OwnerID         int64          `db:"owner_id"`
+	OwnerName       string         `db:"owner_name"`
+	OwnerSurname    string         `db:"owner_surname"`
+	OwnerPatronymic string         `db:"owner_patronymic"`
+}
+
+func (c row) car() dto.Car {
+	car := dto.Car{
+		ID:     c.CarID,
+		RegNum: c.RegNum,
+		Mark:   c.Mark,
+		Model:  c.Model,
+		Year:   c.Year,
+		Owner: dto.Owner{
+			ID:         c.OwnerID,
+			Name:       c.OwnerName,
+			Surname:    c.OwnerSurname,
+			Patronymic: c.OwnerPatronymic,
+		},
+	}
+
+	if c.MarkID.Valid {
+		car.MarkKnown = true
+		car.MarkID = &c.MarkID.Int64
+	}
+
+	if c.ModelID.Valid {
+		car.ModelKnown = true
+		car.ModelID = &c.ModelID.Int64
+	}
+
+	if c.Region.Valid {
+		subject, _ := plate.Subject(c.Region.String)
+
+		car.Region = &dto.Region{
+			Code:    c.Region.String,
+			Subject: subject,
+		}
+	}
+
+	if c.Color.Valid {
+		car.Color = &c.Color.String
+	}
+
+	if c.BodyType.Valid {
+		bodyType := dto.BodyType(c.BodyType.String)
+		car.BodyType = &bodyType
+	}
+
+	if c.FuelType.Valid {
+		fuelType := dto.FuelType(c.FuelType.String)
+		car.FuelType = &fuelType
+	}
+
+	car.EngineVolume = nullInt(c.EngineVolume)
+	car.Power = nullInt(c.Power)
+	car.Mileage = nullInt(c.Mileage)
+
+	if c.MileageAt.Valid {
+		car.MileageAt = &c.MileageAt.Time
+	}
+
+	if c.VIN.Valid {
+		car.VIN = &c.VIN.String
+
+		if decoded, err := vin.Parse(c.VIN.String); err == nil {
+			car.VINInfo = &dto.VINInfo{
+				WMI:             decoded.WMI,
+				Manufacturer:    decoded.Manufacturer,
+				ModelYear:       decoded.ModelYear,
+				CheckDigitValid: decoded.CheckDigitValid,
+			}
+		}
+	}
+
+	return car
+}
+
+func nullInt(
+	value sql.NullInt64,
+) *int {
+
+	if !value.Valid {
+		return nil
+	}
+
+	v := int(value.Int64)
+
+	return &v
+}
+
+func (r Repository) selectCars() sq.SelectBuilder {
+	return sq.
+		Select(
+			"car.id AS car_id",
+			"car.regnum AS car_regnum",
+			"car.mark AS car_mark",
+			"car.model AS car_model",
+			"car.mark_id AS car_mark_id",
+			"car.model_id AS car_model_id",
+			"car.year AS car_year",
+			"car.region AS car_region",
+			"car.vin AS car_vin",
+			"car.color AS car_color",
+			"car.body_type AS car_body_type",
+			"car.fuel_type AS car_fuel_type",
+			"car.engine_volume AS car_engine_volume",
+			"car.power AS car_power",
+			"car.mileage AS car_mileage",
+			"car.mileage_at AS car_mileage_at",
+			"owner.id AS owner_id",
+			"owner.name AS owner_name",
+			"owner.surname AS owner_surname",
+			"owner.patronymic AS owner_patronymic",
+		).
+		From("car").
+		LeftJoin("owner ON car.owner_id = owner.id").
+		PlaceholderFormat(sq.Dollar)
+}
+
 func (r Repository) Create(
 	ctx context.Context,
 	cars map[int64]dto.Car,
 ) error {
 
+	ordered := make([]dto.Car, 0, len(cars))
+
 	insertBuilder := sq.
 		Insert("car").
-		Columns("regNum", "mark", "model", "year", "owner_id")
+		Columns(
+			"regNum", "mark", "model", "mark_id", "model_id", "year", "region", "vin",
+			"color", "body_type", "fuel_type", "engine_volume", "power", "mileage", "mileage_at",
+			"owner_id",
+		).
+		Suffix("RETURNING id")
 
 	for _, car := range cars {
+		ordered = append(ordered, car)
+
+		var region *string
+		if car.Region != nil {
+			region = &car.Region.Code
+		}
+
 		insertBuilder = insertBuilder.Values(
-			car.RegNum, car.Mark, car.Model, car.Year, car.Owner.ID,
+			car.RegNum, car.Mark, car.Model, car.MarkID, car.ModelID,
+			car.Year, region, car.VIN,
+			car.Color, car.BodyType, car.FuelType, car.EngineVolume, car.Power, car.Mileage, car.MileageAt,
+			car.Owner.ID,
 		)
 	}
 
 	query, args, err := insertBuilder.PlaceholderFormat(sq.Dollar).ToSql()
 
-	logger := r.logger.WithFields(map[string]any{
+	logger := r.logger.WithContext(ctx).WithFields(map[string]any{
 		"request": map[string]any{
 			"query": query,
 			"args": map[string]any{
@@ -63,169 +232,300 @@ func (r Repository) Create(
 		return errors.ErrInternal.New("can't create car").Wrap(err)
 	}
 
-	result, err := r.db.ExecContext(ctx, query, args...)
-	if err != nil {
-		if errpkg.Is(err, sql.ErrNoRows) {
-			logger.Warnf("can't create car: %s", err)
+	return postgres.Transaction(ctx, r.db, "create car", r.logger, func(tx *sqlx.Tx) error {
+		carIds := make([]int64, 0, len(ordered))
 
-			return errors.ErrInternal.New("can't create car").Wrap(err)
-		}
+		if err := tx.SelectContext(ctx, &carIds, query, args...); err != nil {
+			if e, ok := err.(*pq.Error); ok {
+				switch e.Code {
 
-		if e, ok := err.(*pq.Error); ok {
-			switch e.Code {
+				case pgerr.UniqueViolation:
+					if e.Constraint == "unique_car_vin" {
+						logger.Warnf("vin already exists: %s", err)
 
-			case pgerr.UniqueViolation:
-				logger.Warnf("car already exists: %s", err)
+						return errors.ErrAlreadyExists.New("car with this vin already exists").Wrap(err)
+					}
 
-				return errors.ErrAlreadyExists.New("car already exists").Wrap(err)
+					logger.Warnf("car already exists: %s", err)
 
-			case pgerr.ForeignKeyViolation:
-				logger.Warnf("owner not found: %s", err)
+					return errors.ErrAlreadyExists.New("car already exists").Wrap(err)
 
-				return errors.ErrNotFound.New("owner not found").Wrap(err)
+				case pgerr.ForeignKeyViolation:
+					logger.Warnf("owner not found: %s", err)
 
-			default:
-				logger.Warnf("can't create car: %s", err)
+					return errors.ErrNotFound.New("owner not found").Wrap(err)
 
-				return errors.ErrInternal.New("can't create car").Wrap(err)
+				default:
+					logger.Warnf("can't create car: %s", err)
+
+					return errors.ErrInternal.New("can't create car").Wrap(err)
+				}
 			}
+
+			logger.Warnf("can't create car: %s", err)
+
+			return errors.ErrInternal.New("can't create car").Wrap(err)
 		}
 
-		logger.Warnf("can't create car: %s", err)
+		if len(carIds) != len(ordered) {
+			logger.Warnf("can't create car: rows affected %d", len(carIds))
 
-		return errors.ErrInternal.New("can't create car").Wrap(err)
-	}
+			return errors.ErrInternal.New("can't create car")
+		}
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil || rowsAffected != int64(len(cars)) {
-		logger.Warnf("can't create car: %s", err)
+		logger.Debugf("rows affected: %d", len(carIds))
 
-		return errors.ErrInternal.New("can't create car").Wrap(err)
-	}
+		records := make([]dto.CreateAudit, len(ordered))
+		for i, car := range ordered {
+			car.ID = carIds[i]
 
-	logger.Debugf("rows affected: %d", rowsAffected)
+			records[i] = dto.CreateAudit{
+				Entity:   dto.AuditEntityCar,
+				EntityID: car.ID,
+				Action:   dto.AuditActionCreate,
+				After:    car,
+			}
+		}
 
-	return nil
+		return r.audit.Write(ctx, tx, records...)
+	})
 }
 
 func (r Repository) Get(
 	ctx context.Context,
 	filter dto.Filter,
 	pagination dto.Pagination,
-) ([]dto.Car, error) {
+) (dto.CarList, error) {
 
 	var (
 		offset = uint64(pagination.Offset)
 		limit  = uint64(pagination.Limit)
 	)
 
-	selectBuilder := sq.
-		Select(
-			"car.id AS car_id",
-			"car.regnum AS car_regnum",
-			"car.mark AS car_mark",
-			"car.model AS car_model",
-			"car.year AS car_year",
-			"owner.id AS owner_id",
-			"owner.name AS owner_name",
-			"owner.surname AS owner_surname",
-			"owner.patronymic AS owner_patronymic",
-		).
-		From("car").
-		LeftJoin("owner ON car.owner_id = owner.id").
-		OrderBy("car.id DESC").
-		Offset(offset).
-		Limit(limit).
-		PlaceholderFormat(sq.Dollar)
+	orders, err := r.orders(pagination.Sort)
+	if err != nil {
+		r.logger.WithContext(ctx).Warnf("invalid sort: %s", err)
+
+		return dto.CarList{}, err
+	}
+
+	// Запрашиваем на одну строку больше, чтобы понять,
+	// есть ли следующая страница
+	selectBuilder := r.orderBy(r.selectCars(), orders).
+		Limit(limit + 1)
 
-	selectBuilder = r.where(selectBuilder, filter)
+	if pagination.Cursor != "" {
+		values, err := r.decodeCursor(pagination.Cursor, orders)
+		if err != nil {
+			r.logger.WithContext(ctx).Warnf("invalid cursor: %s", err)
+
+			return dto.CarList{}, errors.ErrInvalid.New("invalid cursor").Wrap(err)
+		}
+
+		selectBuilder = selectBuilder.Where(r.keyset(orders, values))
+	} else {
+		selectBuilder = selectBuilder.Offset(offset)
+	}
+
+	selectBuilder = r.where(ctx, selectBuilder, filter)
 
 	query, args, err := selectBuilder.ToSql()
 
-	logger := r.logger.WithFields(map[string]any{
+	logger := r.logger.WithContext(ctx).WithFields(map[string]any{
 		"query": query,
 		"args": map[string]any{
 			"limit":  pagination.Limit,
 			"offset": pagination.Offset,
+			"cursor": pagination.Cursor,
+			"sort":   pagination.Sort,
 		},
 	})
 
 	if err != nil {
 		logger.Warnf("can't get cars: %s", err)
 
-		return []dto.Car{}, errors.ErrInternal.New("can't get cars").Wrap(err)
+		return dto.CarList{}, errors.ErrInternal.New("can't get cars").Wrap(err)
 	}
 
-	type car struct {
-		CarID           int64  `db:"car_id"`
-		RegNum          string `db:"car_regnum"`
-		Mark            string `db:"car_mark"`
-		Model           string `db:"car_model"`
-		Year            int    `db:"car_year"`
-		OwnerID         int64  `db:"owner_id"`
-		OwnerName       string `db:"owner_name"`
-		OwnerSurname    string `db:"owner_surname"`
-		OwnerPatronymic string `db:"owner_patronymic"`
-	}
-
-	rawCars := make([]car, 0)
+	rawCars := make([]row, 0)
 
 	if err := r.db.SelectContext(ctx, &rawCars, query, args...); err != nil {
 		if !errpkg.Is(err, sql.ErrNoRows) {
 			logger.Warnf("can't get cars: %s", err)
 
-			return []dto.Car{}, errors.ErrInternal.New("can't get cars").Wrap(err)
+			return dto.CarList{}, errors.ErrInternal.New("can't get cars").Wrap(err)
 		}
 
 		logger.Warnf("no cars: %s", err)
 
-		return []dto.Car{}, errors.ErrNotFound.New("no cars").Wrap(err)
+		return dto.CarList{}, errors.ErrNotFound.New("no cars").Wrap(err)
+	}
+
+	hasNext := uint64(len(rawCars)) > limit
+	if hasNext {
+		rawCars = rawCars[:limit]
+	}
+
+	cars := dto.CarList{
+		Items: make([]dto.Car, len(rawCars)),
 	}
 
-	cars := make([]dto.Car, len(rawCars))
 	for i, rawCar := range rawCars {
-		cars[i] = dto.Car{
-			ID:     rawCar.CarID,
-			RegNum: rawCar.RegNum,
-			Mark:   rawCar.Mark,
-			Model:  rawCar.Model,
-			Year:   rawCar.Year,
-			Owner: dto.Owner{
-				ID:         rawCar.OwnerID,
-				Name:       rawCar.OwnerName,
-				Surname:    rawCar.OwnerSurname,
-				Patronymic: rawCar.OwnerPatronymic,
-			},
+		cars.Items[i] = rawCar.car()
+	}
+
+	if hasNext {
+		nextCursor, err := r.encodeCursor(orders, cars.Items[len(cars.Items)-1])
+		if err != nil {
+			logger.Warnf("can't encode cursor: %s", err)
+
+			return dto.CarList{}, errors.ErrInternal.New("can't get cars").Wrap(err)
 		}
+
+		cars.NextCursor = nextCursor
+	}
+
+	if !pagination.WithTotal {
+		return cars, nil
 	}
 
+	total, err := r.count(ctx, r.db, filter)
+	if err != nil {
+		return dto.CarList{}, err
+	}
+
+	cars.Total = total
+
 	return cars, nil
 }
 
+func (r Repository) count(
+	ctx context.Context,
+	db sqlx.QueryerContext,
+	filter dto.Filter,
+) (int64, error) {
+
+	selectBuilder := sq.
+		Select("COUNT(*)").
+		From("car").
+		LeftJoin("owner ON car.owner_id = owner.id").
+		PlaceholderFormat(sq.Dollar)
+
+	selectBuilder = r.where(ctx, selectBuilder, filter)
+
+	query, args, err := selectBuilder.ToSql()
+
+	logger := r.logger.WithContext(ctx).WithFields(map[string]any{
+		"query": query,
+		"args": map[string]any{
+			"filter": filter,
+		},
+	})
+
+	if err != nil {
+		logger.Warnf("can't count cars: %s", err)
+
+		return 0, errors.ErrInternal.New("can't count cars").Wrap(err)
+	}
+
+	var total int64
+
+	if err := sqlx.GetContext(ctx, db, &total, query, args...); err != nil {
+		logger.Warnf("can't count cars: %s", err)
+
+		return 0, errors.ErrInternal.New("can't count cars").Wrap(err)
+	}
+
+	return total, nil
+}
+
+// Iterate построчно читает все автомобили, подходящие под фильтр,
+// и передаёт их в fn, не загружая выборку в память целиком.
+// Ошибка fn прерывает чтение и возвращается как есть
+func (r Repository) Iterate(
+	ctx context.Context,
+	filter dto.Filter,
+	sort []dto.Sort,
+	fn func(dto.Car) error,
+) error {
+
+	orders, err := r.orders(sort)
+	if err != nil {
+		r.logger.WithContext(ctx).Warnf("invalid sort: %s", err)
+
+		return err
+	}
+
+	selectBuilder := r.where(ctx, r.orderBy(r.selectCars(), orders), filter)
+
+	query, args, err := selectBuilder.ToSql()
+
+	logger := r.logger.WithContext(ctx).WithFields(map[string]any{
+		"query": query,
+		"args": map[string]any{
+			"filter": filter,
+			"sort":   sort,
+		},
+	})
+
+	if err != nil {
+		logger.Warnf("can't iterate cars: %s", err)
+
+		return errors.ErrInternal.New("can't get cars").Wrap(err)
+	}
+
+	rows, err := r.db.QueryxContext(ctx, query, args...)
+	if err != nil {
+		logger.Warnf("can't iterate cars: %s", err)
+
+		return errors.ErrInternal.New("can't get cars").Wrap(err)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		rawCar := row{}
+
+		if err := rows.StructScan(&rawCar); err != nil {
+			logger.Warnf("can't scan car: %s", err)
+
+			return errors.ErrInternal.New("can't get cars").Wrap(err)
+		}
+
+		if err := fn(rawCar.car()); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Warnf("can't iterate cars: %s", err)
+
+		return errors.ErrInternal.New("can't get cars").Wrap(err)
+	}
+
+	return nil
+}
+
 func (r Repository) GetById(
 	ctx context.Context,
 	id int64,
 ) (dto.Car, error) {
 
-	query, args, err := sq.
-		Select(
-			"car.id AS car_id",
-			"car.regnum AS car_regnum",
-			"car.mark AS car_mark",
-			"car.model AS car_model",
-			"car.year AS car_year",
-			"owner.id AS owner_id",
-			"owner.name AS owner_name",
-			"owner.surname AS owner_surname",
-			"owner.patronymic AS owner_patronymic",
-		).
-		From("car").
-		LeftJoin("owner ON car.owner_id = owner.id").
+	return r.getById(ctx, r.db, r.selectCars(), id)
+}
+
+func (r Repository) getById(
+	ctx context.Context,
+	db sqlx.QueryerContext,
+	selectBuilder sq.SelectBuilder,
+	id int64,
+) (dto.Car, error) {
+
+	query, args, err := selectBuilder.
 		Where(sq.Eq{"car.id": id}).
-		PlaceholderFormat(sq.Dollar).
 		ToSql()
 
-	logger := r.logger.WithFields(map[string]any{
+	logger := r.logger.WithContext(ctx).WithFields(map[string]any{
 		"query": query,
 		"args": map[string]any{
 			"id": id,
@@ -238,21 +538,9 @@ func (r Repository) GetById(
 		return dto.Car{}, errors.ErrInternal.New("can't get car").Wrap(err)
 	}
 
-	type car struct {
-		CarID           int64  `db:"car_id"`
-		RegNum          string `db:"car_regnum"`
-		Mark            string `db:"car_mark"`
-		Model           string `db:"car_model"`
-		Year            int    `db:"car_year"`
-		OwnerID         int64  `db:"owner_id"`
-		OwnerName       string `db:"owner_name"`
-		OwnerSurname    string `db:"owner_surname"`
-		OwnerPatronymic string `db:"owner_patronymic"`
-	}
-
-	rawCar := car{}
+	rawCar := row{}
 
-	if err := r.db.GetContext(ctx, &rawCar, query, args...); err != nil {
+	if err := sqlx.GetContext(ctx, db, &rawCar, query, args...); err != nil {
 		logger.Warnf("can't get car: %s", err)
 
 		if !errpkg.Is(err, sql.ErrNoRows) {
@@ -262,19 +550,18 @@ func (r Repository) GetById(
 		return dto.Car{}, errors.ErrNotFound.New("car not found").Wrap(err)
 	}
 
-	return dto.Car{
-		ID:     rawCar.CarID,
-		RegNum: rawCar.RegNum,
-		Mark:   rawCar.Mark,
-		Model:  rawCar.Model,
-		Year:   rawCar.Year,
-		Owner: dto.Owner{
-			ID:         rawCar.OwnerID,
-			Name:       rawCar.OwnerName,
-			Surname:    rawCar.OwnerSurname,
-			Patronymic: rawCar.OwnerPatronymic,
-		},
-	}, nil
+	return rawCar.car(), nil
+}
+
+// lockById читает автомобиль внутри транзакции, блокируя строку
+// до её завершения, чтобы состояние "до" в аудите было точным.
+func (r Repository) lockById(
+	ctx context.Context,
+	tx *sqlx.Tx,
+	id int64,
+) (dto.Car, error) {
+
+	return r.getById(ctx, tx, r.selectCars().Suffix("FOR UPDATE OF car"), id)
 }
 
 func (r Repository) Update(
@@ -282,52 +569,58 @@ func (r Repository) Update(
 	update dto.Car,
 ) error {
 
-	rollback := func(tx *sqlx.Tx) error {
-		if err := tx.Rollback(); err != nil {
-			r.logger.Warnf("unknown error on rollback: %s", err)
-
-			return errors.ErrInternal.New("can't update car").Wrap(err)
+	return postgres.Transaction(ctx, r.db, "update car", r.logger, func(tx *sqlx.Tx) error {
+		before, err := r.lockById(ctx, tx, update.ID)
+		if err != nil {
+			return err
 		}
 
-		return nil
-	}
-
-	commit := func(tx *sqlx.Tx) error {
-		if err := tx.Commit(); err != nil {
-			r.logger.Warnf("unknown error on commit: %s", err)
+		transfer := update.Owner.ID != before.Owner.ID
 
-			return errors.ErrInternal.New("can't update car").Wrap(err)
+		if err := r.updateCar(ctx, tx, update); err != nil {
+			return err
 		}
 
-		return nil
-	}
-
-	r.logger.Info("starting update car transaction")
-
-	tx, err := r.db.BeginTxx(ctx, nil)
-	if err != nil {
-		r.logger.Warnf("can't start transaction: %s", err)
-
-		return errors.ErrInternal.New("can't update car").Wrap(err)
-	}
+		// При передаче имя владельца не меняется: тело запроса, повторяющее
+		// ответ GET, иначе переименовало бы нового владельца в прежнего
+		if !transfer {
+			if err := r.updateOwner(ctx, tx, update.Owner); err != nil {
+				return err
+			}
+		}
 
-	if err := r.updateCar(ctx, tx, update); err != nil {
-		if err := rollback(tx); err != nil {
+		after, err := r.getById(ctx, tx, r.selectCars(), update.ID)
+		if err != nil {
 			return err
 		}
 
-		return err
-	}
+		action := dto.AuditActionUpdate
+		if transfer {
+			action = dto.AuditActionTransfer
+		}
 
-	if err := r.updateOwner(ctx, tx, update.Owner); err != nil {
-		if err := rollback(tx); err != nil {
-			return err
+		records := []dto.CreateAudit{
+			{
+				Entity:   dto.AuditEntityCar,
+				EntityID: update.ID,
+				Action:   action,
+				Before:   before,
+				After:    after,
+			},
 		}
 
-		return err
-	}
+		if !transfer && before.Owner != after.Owner {
+			records = append(records, dto.CreateAudit{
+				Entity:   dto.AuditEntityOwner,
+				EntityID: after.Owner.ID,
+				Action:   dto.AuditActionUpdate,
+				Before:   before.Owner,
+				After:    after.Owner,
+			})
+		}
 
-	return commit(tx)
+		return r.audit.Write(ctx, tx, records...)
+	})
 }
 
 func (r Repository) updateCar(
@@ -346,7 +639,7 @@ func (r Repository) updateCar(
 		PlaceholderFormat(sq.Dollar).
 		ToSql()
 
-	logger := r.logger.WithFields(map[string]any{
+	logger := r.logger.WithContext(ctx).WithFields(map[string]any{
 		"request": map[string]any{
 			"query": query,
 			"args":  values,
@@ -372,6 +665,12 @@ func (r Repository) updateCar(
 			switch e.Code {
 
 			case pgerr.UniqueViolation:
+				if e.Constraint == "unique_car_vin" {
+					logger.Warnf("vin already exists: %s", err)
+
+					return errors.ErrAlreadyExists.New("car with this vin already exists").Wrap(err)
+				}
+
 				logger.Warnf("car already exists: %s", err)
 
 				return errors.ErrAlreadyExists.New("car already exists").Wrap(err)
@@ -412,7 +711,7 @@ func (r Repository) updateOwner(
 		PlaceholderFormat(sq.Dollar).
 		ToSql()
 
-	logger := r.logger.WithFields(map[string]any{
+	logger := r.logger.WithContext(ctx).WithFields(map[string]any{
 		"request": map[string]any{
 			"query": query,
 			"args":  values,
@@ -463,22 +762,63 @@ func (r Repository) updateCarValues(
 
 	u := map[string]any{}
 
+	if update.Owner.ID != 0 {
+		u["owner_id"] = update.Owner.ID
+	}
+
+	// Ссылки на справочник меняются вместе с названиями,
+	// в том числе сбрасываются для неизвестных значений
 	if len(update.Mark) != 0 {
 		u["mark"] = update.Mark
+		u["mark_id"] = update.MarkID
 	}
 
 	if len(update.Model) != 0 {
 		u["model"] = update.Model
+		u["model_id"] = update.ModelID
 	}
 
 	if len(update.RegNum) != 0 {
 		u["regNum"] = update.RegNum
 	}
 
+	if update.Region != nil {
+		u["region"] = update.Region.Code
+	}
+
+	if update.VIN != nil {
+		u["vin"] = *update.VIN
+	}
+
 	if update.Year != 0 {
 		u["year"] = update.Year
 	}
 
+	if update.Color != nil {
+		u["color"] = *update.Color
+	}
+
+	if update.BodyType != nil {
+		u["body_type"] = *update.BodyType
+	}
+
+	if update.FuelType != nil {
+		u["fuel_type"] = *update.FuelType
+	}
+
+	if update.EngineVolume != nil {
+		u["engine_volume"] = *update.EngineVolume
+	}
+
+	if update.Power != nil {
+		u["power"] = *update.Power
+	}
+
+	if update.Mileage != nil {
+		u["mileage"] = *update.Mileage
+		u["mileage_at"] = update.MileageAt
+	}
+
 	return u
 }
 
@@ -515,7 +855,7 @@ func (r Repository) Delete(
 		PlaceholderFormat(sq.Dollar).
 		ToSql()
 
-	logger := r.logger.WithFields(map[string]any{
+	logger := r.logger.WithContext(ctx).WithFields(map[string]any{
 		"query": query,
 		"args": map[string]any{
 			"car": car,
@@ -528,19 +868,31 @@ func (r Repository) Delete(
 		return errors.ErrInternal.New("can't delete owner").Wrap(err)
 	}
 
-	var carId int
+	return postgres.Transaction(ctx, r.db, "delete car", r.logger, func(tx *sqlx.Tx) error {
+		before, err := r.lockById(ctx, tx, car.ID)
+		if err != nil {
+			return err
+		}
 
-	if err := r.db.GetContext(ctx, &carId, query, args...); err != nil {
-		if !errpkg.Is(err, sql.ErrNoRows) {
-			logger.Warnf("can't delete car: %s", err)
+		var carId int
 
-			return errors.ErrInternal.New("can't delete car").Wrap(err)
-		}
+		if err := tx.GetContext(ctx, &carId, query, args...); err != nil {
+			if !errpkg.Is(err, sql.ErrNoRows) {
+				logger.Warnf("can't delete car: %s", err)
 
-		logger.Warnf("car not found: %s", err)
+				return errors.ErrInternal.New("can't delete car").Wrap(err)
+			}
 
-		return errors.ErrNotFound.New("car not found").Wrap(err)
-	}
+			logger.Warnf("car not found: %s", err)
 
-	return nil
+			return errors.ErrNotFound.New("car not found").Wrap(err)
+		}
+
+		return r.audit.Write(ctx, tx, dto.CreateAudit{
+			Entity:   dto.AuditEntityCar,
+			EntityID: car.ID,
+			Action:   dto.AuditActionDelete,
+			Before:   before,
+		})
+	})
 }
@@ -0,0 +1,180 @@
+package car
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackvonhouse/car-enrichment/internal/dto"
+	"strings"
+)
+
+// order описывает один ключ сортировки: SQL-выражение
+// и способ получить его значение из автомобиля для курсора
+type order struct {
+	column  string
+	desc    bool
+	numeric bool
+	value   func(dto.Car) any
+}
+
+var orderById = order{
+	column:  "car.id",
+	desc:    true,
+	numeric: true,
+	value:   func(c dto.Car) any { return c.ID },
+}
+
+type cursor struct {
+	Signature string `json:"s"`
+	Values    []any  `json:"v"`
+}
+
+func (r Repository) orderBy(
+	builder sq.SelectBuilder,
+	orders []order,
+) sq.SelectBuilder {
+
+	for _, o := range orders {
+		direction := "ASC"
+		if o.desc {
+			direction = "DESC"
+		}
+
+		builder = builder.OrderBy(fmt.Sprintf("%s %s", o.column, direction))
+	}
+
+	return builder
+}
+
+// signature привязывает курсор к порядку сортировки,
+// чтобы курсор одной сортировки нельзя было применить к другой
+func (r Repository) signature(
+	orders []order,
+) string {
+
+	parts := make([]string, len(orders))
+	for i, o := range orders {
+		parts[i] = o.column
+		if o.desc {
+			parts[i] = "-" + o.column
+		}
+	}
+
+	return strings.Join(parts, ",")
+}
+
+func (r Repository) encodeCursor(
+	orders []order,
+	car dto.Car,
+) (string, error) {
+
+	c := cursor{
+		Signature: r.signature(orders),
+		Values:    make([]any, len(orders)),
+	}
+
+	for i, o := range orders {
+		c.Values[i] = o.value(car)
+	}
+
+	data, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func (r Repository) decodeCursor(
+	raw string,
+	orders []order,
+) ([]any, error) {
+
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	c := cursor{}
+
+	if err := decoder.Decode(&c); err != nil {
+		return nil, err
+	}
+
+	if c.Signature != r.signature(orders) {
+		return nil, fmt.Errorf("cursor sort mismatch")
+	}
+
+	if len(c.Values) != len(orders) {
+		return nil, fmt.Errorf("cursor values mismatch")
+	}
+
+	values := make([]any, len(orders))
+
+	for i, o := range orders {
+		value, err := o.parse(c.Values[i])
+		if err != nil {
+			return nil, err
+		}
+
+		values[i] = value
+	}
+
+	return values, nil
+}
+
+// parse проверяет тип значения из курсора, чтобы подделанный курсор
+// с объектом или массивом не дошёл до базы
+func (o order) parse(
+	value any,
+) (any, error) {
+
+	if o.numeric {
+		number, ok := value.(json.Number)
+		if !ok {
+			return nil, fmt.Errorf("cursor value for %s must be a number", o.column)
+		}
+
+		return number.Int64()
+	}
+
+	text, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("cursor value for %s must be a string", o.column)
+	}
+
+	return text, nil
+}
+
+// keyset строит условие "строго после курсора" для составного ключа:
+// (k1 > v1) OR (k1 = v1 AND k2 > v2) OR ...
+func (r Repository) keyset(
+	orders []order,
+	values []any,
+) sq.Sqlizer {
+
+	condition := sq.Or{}
+
+	for i, o := range orders {
+		and := sq.And{}
+
+		for j := 0; j < i; j++ {
+			and = append(and, sq.Eq{orders[j].column: values[j]})
+		}
+
+		if o.desc {
+			and = append(and, sq.Lt{o.column: values[i]})
+		} else {
+			and = append(and, sq.Gt{o.column: values[i]})
+		}
+
+		condition = append(condition, and)
+	}
+
+	return condition
+}
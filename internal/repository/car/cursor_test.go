@@ -0,0 +1,64 @@
+package car
+
+import (
+	"encoding/base64"
+	"github.com/jackvonhouse/car-enrichment/internal/dto"
+	"reflect"
+	"testing"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	r := Repository{}
+
+	orders, err := r.orders([]dto.Sort{{Field: "mark"}, {Field: "year", Desc: true}})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	raw, err := r.encodeCursor(orders, dto.Car{ID: 42, Mark: "Lada", Year: 2015})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	values, err := r.decodeCursor(raw, orders)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if want := []any{"Lada", int64(2015), int64(42)}; !reflect.DeepEqual(values, want) {
+		t.Errorf("expected %v, got %v", want, values)
+	}
+}
+
+func TestDecodeCursorRejects(t *testing.T) {
+	r := Repository{}
+
+	orders, err := r.orders([]dto.Sort{{Field: "mark"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{name: "object", cursor: `{"s":"car.mark,-car.id","v":[{"a":1},42]}`},
+		{name: "array", cursor: `{"s":"car.mark,-car.id","v":["Lada",[42]]}`},
+		{name: "number for text", cursor: `{"s":"car.mark,-car.id","v":[1,42]}`},
+		{name: "text for number", cursor: `{"s":"car.mark,-car.id","v":["Lada","42"]}`},
+		{name: "fraction", cursor: `{"s":"car.mark,-car.id","v":["Lada",4.2]}`},
+		{name: "null", cursor: `{"s":"car.mark,-car.id","v":["Lada",null]}`},
+		{name: "values count", cursor: `{"s":"car.mark,-car.id","v":["Lada"]}`},
+		{name: "signature", cursor: `{"s":"car.model,-car.id","v":["Lada",42]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw := base64.RawURLEncoding.EncodeToString([]byte(tt.cursor))
+
+			if _, err := r.decodeCursor(raw, orders); err == nil {
+				t.Errorf("expected error for %s", tt.cursor)
+			}
+		})
+	}
+}
@@ -1,136 +1,129 @@
 package car
 
 import (
+	"context"
 	"fmt"
 	sq "github.com/Masterminds/squirrel"
 	"github.com/jackvonhouse/car-enrichment/internal/dto"
 	"strings"
 )
 
+var likeEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	`%`, `\%`,
+	`_`, `\_`,
+)
+
 func (r Repository) where(
+	ctx context.Context,
 	builder sq.SelectBuilder,
 	filter dto.Filter,
 ) sq.SelectBuilder {
 
-	builder = r.whereName(builder, filter.OwnerName)
-	builder = r.whereSurname(builder, filter.OwnerSurname)
-	builder = r.wherePatronymic(builder, filter.OwnerPatronymic)
-	builder = r.whereRegNumber(builder, filter.RegNum)
-	builder = r.whereMark(builder, filter.Mark)
-	builder = r.whereModel(builder, filter.Model)
-	builder = r.whereYear(builder, filter.Year)
+	builder = r.whereConditions(ctx, builder, "owner.name", filter.OwnerName)
+	builder = r.whereConditions(ctx, builder, "owner.surname", filter.OwnerSurname)
+	builder = r.whereConditions(ctx, builder, "owner.patronymic", filter.OwnerPatronymic)
+	builder = r.whereConditions(ctx, builder, "car.regNum", filter.RegNum)
+	builder = r.whereConditions(ctx, builder, "car.mark", filter.Mark)
+	builder = r.whereConditions(ctx, builder, "car.model", filter.Model)
+	builder = r.whereConditions(ctx, builder, "car.year", filter.Year)
+	builder = r.whereConditions(ctx, builder, "car.region", filter.Region)
+	builder = r.whereConditions(ctx, builder, "car.vin", filter.VIN)
+	builder = r.whereConditions(ctx, builder, "car.color", filter.Color)
+	builder = r.whereConditions(ctx, builder, "car.body_type", filter.BodyType)
+	builder = r.whereConditions(ctx, builder, "car.fuel_type", filter.FuelType)
+	builder = r.whereConditions(ctx, builder, "car.engine_volume", filter.EngineVolume)
+	builder = r.whereConditions(ctx, builder, "car.power", filter.Power)
+	builder = r.whereConditions(ctx, builder, "car.mileage", filter.Mileage)
+	// Дата пробега сравнивается по дням, без учёта времени
+	builder = r.whereConditions(ctx, builder, "car.mileage_at::date", filter.MileageAt)
 
 	return builder
 }
 
-func (r Repository) whereName(
+func (r Repository) whereConditions(
+	ctx context.Context,
 	builder sq.SelectBuilder,
-	name string,
+	column string,
+	conditions []dto.Condition,
 ) sq.SelectBuilder {
 
-	if len(strings.TrimSpace(name)) == 0 {
-		return builder
+	for _, condition := range conditions {
+		builder = builder.Where(r.condition(ctx, column, condition))
 	}
 
-	return builder.Where(
-		sq.Like{
-			"owner.name": fmt.Sprintf("%%%s%%", name),
-		},
-	)
+	return builder
 }
 
-func (r Repository) whereSurname(
-	builder sq.SelectBuilder,
-	surname string,
-) sq.SelectBuilder {
+// condition ожидает условие, уже проверенное на уровне транспорта:
+// количество значений соответствует оператору
+func (r Repository) condition(
+	ctx context.Context,
+	column string,
+	condition dto.Condition,
+) sq.Sqlizer {
 
-	if len(strings.TrimSpace(surname)) == 0 {
-		return builder
-	}
+	values := condition.Values
 
-	return builder.Where(
-		sq.Like{
-			"owner.surname": fmt.Sprintf("%%%s%%", surname),
-		},
-	)
-}
+	switch condition.Operator {
 
-func (r Repository) wherePatronymic(
-	builder sq.SelectBuilder,
-	patronymic string,
-) sq.SelectBuilder {
+	case dto.OperatorEq:
+		return sq.Eq{column: values[0]}
 
-	if len(strings.TrimSpace(patronymic)) == 0 {
-		return builder
-	}
+	case dto.OperatorNe:
+		return sq.NotEq{column: values[0]}
 
-	return builder.Where(
-		sq.Like{
-			"owner.patronymic": fmt.Sprintf("%%%s%%", patronymic),
-		},
-	)
-}
+	case dto.OperatorIn:
+		return sq.Eq{column: values}
 
-func (r Repository) whereRegNumber(
-	builder sq.SelectBuilder,
-	regNumber string,
-) sq.SelectBuilder {
+	case dto.OperatorLike:
+		return r.normalizedLike(column, fmt.Sprintf("%%%s%%", r.escapeLike(values[0])))
 
-	if len(strings.TrimSpace(regNumber)) == 0 {
-		return builder
-	}
+	case dto.OperatorPrefix:
+		return r.normalizedLike(column, fmt.Sprintf("%s%%", r.escapeLike(values[0])))
 
-	return builder.Where(
-		sq.Like{
-			"car.regNum": fmt.Sprintf("%%%s%%", regNumber),
-		},
-	)
-}
+	case dto.OperatorSimilar:
+		return sq.Expr(
+			fmt.Sprintf("similarity(search_normalize(%s), search_normalize(?)) >= ?", column),
+			values[0], r.config.SimilarityThreshold,
+		)
 
-func (r Repository) whereMark(
-	builder sq.SelectBuilder,
-	mark string,
-) sq.SelectBuilder {
+	case dto.OperatorGte:
+		return sq.GtOrEq{column: values[0]}
 
-	if len(strings.TrimSpace(mark)) == 0 {
-		return builder
-	}
+	case dto.OperatorLte:
+		return sq.LtOrEq{column: values[0]}
 
-	return builder.Where(
-		sq.Like{
-			"car.mark": fmt.Sprintf("%%%s%%", mark),
-		},
-	)
-}
+	case dto.OperatorBetween:
+		return sq.And{
+			sq.GtOrEq{column: values[0]},
+			sq.LtOrEq{column: values[1]},
+		}
 
-func (r Repository) whereModel(
-	builder sq.SelectBuilder,
-	model string,
-) sq.SelectBuilder {
+	default:
+		r.logger.WithContext(ctx).Warnf("unknown filter operator: %s", condition.Operator)
 
-	if len(strings.TrimSpace(model)) == 0 {
-		return builder
+		return sq.Expr("FALSE")
 	}
+}
 
-	return builder.Where(
-		sq.Like{
-			"car.model": fmt.Sprintf("%%%s%%", model),
-		},
+// normalizedLike сравнивает без учёта регистра и диакритики,
+// выражение совпадает с триграммными индексами из миграции
+func (r Repository) normalizedLike(
+	column string,
+	pattern string,
+) sq.Sqlizer {
+
+	return sq.Expr(
+		fmt.Sprintf("search_normalize(%s) LIKE search_normalize(?)", column),
+		pattern,
 	)
 }
 
-func (r Repository) whereYear(
-	builder sq.SelectBuilder,
-	year int,
-) sq.SelectBuilder {
-
-	if year == 0 {
-		return builder
-	}
+// escapeLike экранирует спецсимволы LIKE во введённом значении
+func (r Repository) escapeLike(
+	value string,
+) string {
 
-	return builder.Where(
-		sq.Eq{
-			"car.year": year,
-		},
-	)
+	return likeEscaper.Replace(value)
 }
@@ -0,0 +1,33 @@
+package car
+
+import (
+	"context"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackvonhouse/car-enrichment/internal/dto"
+	"github.com/jackvonhouse/car-enrichment/pkg/log"
+	"reflect"
+	"testing"
+)
+
+// Фильтр region=77 не должен находить автомобили с регионом 177, 777 и 977
+func TestWhereRegionIsExact(t *testing.T) {
+	r := Repository{logger: log.NewLogrusLogger()}
+
+	filter := dto.Filter{
+		Region: []dto.Condition{{Operator: dto.OperatorEq, Values: []string{"77"}}},
+	}
+
+	query, args, err := r.where(context.Background(), sq.Select("car.id").From("car"), filter).ToSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "SELECT car.id FROM car WHERE car.region = ?"
+	if query != want {
+		t.Errorf("expected %q, got %q", want, query)
+	}
+
+	if !reflect.DeepEqual(args, []any{"77"}) {
+		t.Errorf("expected args [77], got %v", args)
+	}
+}
@@ -0,0 +1,161 @@
+package car
+
+import (
+	"context"
+	"fmt"
+	"github.com/jackvonhouse/car-enrichment/internal/dto"
+	"github.com/jackvonhouse/car-enrichment/internal/errors"
+	"github.com/jackvonhouse/car-enrichment/pkg/plate"
+	"github.com/jackvonhouse/car-enrichment/pkg/vin"
+	"github.com/lib/pq"
+	"strings"
+	"unicode"
+)
+
+// searchFields — поля, по которым ищет полнотекстовый поиск.
+// Для каждого поля отдельно вычисляется, совпало ли оно с запросом
+var searchFields = []struct {
+	name   string
+	column string
+	// pattern — шаблон из q для поиска по подстроке,
+	// для значений, не являющихся словами
+	pattern string
+}{
+	{"regNum", "car.regNum", "q.regnum_pattern"},
+	{"vin", "car.vin", "q.vin_pattern"},
+	{"mark", "car.mark", ""},
+	{"model", "car.model", ""},
+	{"ownerName", "owner.name", ""},
+	{"ownerSurname", "owner.surname", ""},
+	{"ownerPatronymic", "owner.patronymic", ""},
+}
+
+// searchTokens оставляет от запроса только буквы и цифры,
+// поэтому пользовательский ввод не может нарушить синтаксис tsquery
+func (r Repository) searchTokens(
+	query string,
+) []string {
+
+	return strings.FieldsFunc(query, func(c rune) bool {
+		return !unicode.IsLetter(c) && !unicode.IsDigit(c)
+	})
+}
+
+// tsQuery строит запрос по префиксам слов, объединённых оператором
+func (r Repository) tsQuery(
+	tokens []string,
+	operator string,
+) string {
+
+	words := make([]string, len(tokens))
+	for i, token := range tokens {
+		words[i] = token + ":*"
+	}
+
+	return strings.Join(words, " "+operator+" ")
+}
+
+func (r Repository) Search(
+	ctx context.Context,
+	query string,
+	pagination dto.Pagination,
+) ([]dto.SearchResult, error) {
+
+	tokens := r.searchTokens(query)
+	if len(tokens) == 0 {
+		return []dto.SearchResult{}, errors.ErrInvalid.New("empty search query")
+	}
+
+	var (
+		allWords = r.tsQuery(tokens, "&")
+		anyWord  = r.tsQuery(tokens, "|")
+		// Частичные гос. номер и VIN не являются словами, поэтому ищутся
+		// по подстроке. Номер приводится к кириллице, VIN — к латинице
+		regNumPattern = fmt.Sprintf("%%%s%%", r.escapeLike(plate.Normalize(strings.Join(tokens, ""))))
+		vinPattern    = fmt.Sprintf("%%%s%%", r.escapeLike(vin.Normalize(strings.Join(tokens, ""))))
+	)
+
+	selectBuilder := r.selectCars().
+		Prefix(
+			`WITH q AS (
+				SELECT
+					to_tsquery('simple', ?) || to_tsquery('russian', ?) AS all_words,
+					to_tsquery('simple', ?) || to_tsquery('russian', ?) AS any_word,
+					search_normalize(?) AS regnum_pattern,
+					search_normalize(?) AS vin_pattern
+			)`,
+			allWords, allWords, anyWord, anyWord, regNumPattern, vinPattern,
+		).
+		Join("q ON TRUE").
+		Column(`ts_rank(car.search_vector || owner.search_vector, q.all_words) +
+			CASE WHEN search_normalize(car.regNum) LIKE q.regnum_pattern THEN 1 ELSE 0 END +
+			CASE WHEN search_normalize(car.vin) LIKE q.vin_pattern THEN 1 ELSE 0 END AS rank`).
+		Where(`(car.search_vector || owner.search_vector) @@ q.all_words
+			OR search_normalize(car.regNum) LIKE q.regnum_pattern
+			OR search_normalize(car.vin) LIKE q.vin_pattern`).
+		OrderBy("rank DESC", "car.id DESC").
+		Offset(uint64(pagination.Offset)).
+		Limit(uint64(pagination.Limit))
+
+	matches := make([]string, len(searchFields))
+	for i, field := range searchFields {
+		matched := fmt.Sprintf(
+			"(to_tsvector('simple', coalesce(%[1]s, '')) || to_tsvector('russian', coalesce(%[1]s, ''))) @@ q.any_word",
+			field.column,
+		)
+
+		if field.pattern != "" {
+			matched += fmt.Sprintf(" OR search_normalize(%s) LIKE %s", field.column, field.pattern)
+		}
+
+		matches[i] = fmt.Sprintf("CASE WHEN %s THEN '%s' END", matched, field.name)
+	}
+
+	selectBuilder = selectBuilder.Column(fmt.Sprintf(
+		"array_remove(ARRAY[%s], NULL) AS matched",
+		strings.Join(matches, ", "),
+	))
+
+	sqlQuery, args, err := selectBuilder.ToSql()
+
+	logger := r.logger.WithContext(ctx).WithFields(map[string]any{
+		"query": sqlQuery,
+		"args": map[string]any{
+			"search": query,
+			"limit":  pagination.Limit,
+			"offset": pagination.Offset,
+		},
+	})
+
+	if err != nil {
+		logger.Warnf("can't search cars: %s", err)
+
+		return []dto.SearchResult{}, errors.ErrInternal.New("can't search cars").Wrap(err)
+	}
+
+	type result struct {
+		row
+
+		Rank    float64        `db:"rank"`
+		Matched pq.StringArray `db:"matched"`
+	}
+
+	rawResults := make([]result, 0)
+
+	if err := r.db.SelectContext(ctx, &rawResults, sqlQuery, args...); err != nil {
+		logger.Warnf("can't search cars: %s", err)
+
+		return []dto.SearchResult{}, errors.ErrInternal.New("can't search cars").Wrap(err)
+	}
+
+	results := make([]dto.SearchResult, len(rawResults))
+	for i, rawResult := range rawResults {
+		results[i] = dto.SearchResult{
+			Car:     rawResult.car(),
+			Rank:    rawResult.Rank,
+			Matched: rawResult.Matched,
+		}
+	}
+
+	return results, nil
+}
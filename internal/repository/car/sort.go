@@ -0,0 +1,77 @@
+package car
+
+import (
+	"github.com/jackvonhouse/car-enrichment/internal/dto"
+	"github.com/jackvonhouse/car-enrichment/internal/errors"
+)
+
+// sortable — белый список полей сортировки. Выражения не должны
+// возвращать NULL, иначе сравнение по курсору пропустит строки
+var sortable = map[string]order{
+	"id": {
+		column:  "car.id",
+		numeric: true,
+		value:   func(c dto.Car) any { return c.ID },
+	},
+	"regNum": {
+		column: "car.regnum",
+		value:  func(c dto.Car) any { return c.RegNum },
+	},
+	"mark": {
+		column: "car.mark",
+		value:  func(c dto.Car) any { return c.Mark },
+	},
+	"model": {
+		column: "car.model",
+		value:  func(c dto.Car) any { return c.Model },
+	},
+	"year": {
+		column:  "COALESCE(car.year, 0)",
+		numeric: true,
+		value:   func(c dto.Car) any { return c.Year },
+	},
+	"ownerName": {
+		column: "COALESCE(owner.name, '')",
+		value:  func(c dto.Car) any { return c.Owner.Name },
+	},
+	"ownerSurname": {
+		column: "COALESCE(owner.surname, '')",
+		value:  func(c dto.Car) any { return c.Owner.Surname },
+	},
+	"ownerPatronymic": {
+		column: "COALESCE(owner.patronymic, '')",
+		value:  func(c dto.Car) any { return c.Owner.Patronymic },
+	},
+}
+
+// orders переводит запрошенную сортировку в ключи сортировки.
+// Последним ключом всегда идёт car.id, чтобы порядок был детерминированным
+func (r Repository) orders(
+	sort []dto.Sort,
+) ([]order, error) {
+
+	orders := make([]order, 0, len(sort)+1)
+	seen := make(map[string]struct{}, len(sort))
+
+	for _, s := range sort {
+		o, ok := sortable[s.Field]
+		if !ok {
+			return nil, errors.ErrInvalid.New("invalid sort field: " + s.Field)
+		}
+
+		if _, ok := seen[s.Field]; ok {
+			return nil, errors.ErrInvalid.New("duplicate sort field: " + s.Field)
+		}
+
+		seen[s.Field] = struct{}{}
+
+		o.desc = s.Desc
+		orders = append(orders, o)
+	}
+
+	if _, ok := seen["id"]; !ok {
+		orders = append(orders, orderById)
+	}
+
+	return orders, nil
+}
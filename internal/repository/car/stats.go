@@ -0,0 +1,179 @@
+package car
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackvonhouse/car-enrichment/internal/dto"
+	"github.com/jackvonhouse/car-enrichment/internal/errors"
+	"github.com/jackvonhouse/car-enrichment/pkg/plate"
+	"github.com/jmoiron/sqlx"
+)
+
+const statsUnknown = "unknown"
+
+type statsRow struct {
+	Key   sql.NullString `db:"key"`
+	Label sql.NullString `db:"label"`
+	Count int64          `db:"count"`
+}
+
+// Stats считает распределения автомобилей, подходящих под фильтр.
+// Все группировки выполняются в одной транзакции на чтение,
+// поэтому отражают один и тот же снимок данных
+func (r Repository) Stats(
+	ctx context.Context,
+	filter dto.Filter,
+	options dto.StatsOptions,
+) (dto.CarStats, error) {
+
+	stats := dto.CarStats{}
+
+	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelRepeatableRead,
+		ReadOnly:  true,
+	})
+	if err != nil {
+		r.logger.WithContext(ctx).Warnf("can't start transaction: %s", err)
+
+		return dto.CarStats{}, errors.ErrInternal.New("can't get stats").Wrap(err)
+	}
+
+	// Транзакция только читает, поэтому откат после успешного чтения безопасен
+	defer tx.Rollback()
+
+	total, err := r.count(ctx, tx, filter)
+	if err != nil {
+		return dto.CarStats{}, err
+	}
+
+	stats.Total = total
+
+	groups := []struct {
+		target  *[]dto.StatsBucket
+		key     string
+		label   string
+		groupBy []string
+		limit   uint64
+	}{
+		{
+			target:  &stats.ByMark,
+			key:     "car.mark",
+			groupBy: []string{"car.mark"},
+			limit:   uint64(options.Top),
+		},
+		{
+			target:  &stats.ByModel,
+			key:     "car.mark || ' ' || car.model",
+			groupBy: []string{"car.mark", "car.model"},
+			limit:   uint64(options.Top),
+		},
+		{
+			target: &stats.ByYear,
+			key: fmt.Sprintf(
+				"CASE WHEN COALESCE(car.year, 0) = 0 THEN NULL ELSE "+
+					"(car.year / %[1]d * %[1]d)::text || '-' || (car.year / %[1]d * %[1]d + %[1]d - 1)::text END",
+				options.YearBucket,
+			),
+			groupBy: []string{"1"},
+		},
+		{
+			target:  &stats.ByRegion,
+			key:     "car.region",
+			groupBy: []string{"car.region"},
+			limit:   uint64(options.Top),
+		},
+		{
+			target:  &stats.ByOwner,
+			key:     "owner.id::text",
+			label:   "concat_ws(' ', owner.surname, owner.name, NULLIF(owner.patronymic, ''))",
+			groupBy: []string{"owner.id"},
+			limit:   uint64(options.Top),
+		},
+	}
+
+	for _, group := range groups {
+		label := "NULL"
+		if group.label != "" {
+			label = group.label
+		}
+
+		selectBuilder := sq.
+			Select(
+				group.key+" AS key",
+				label+" AS label",
+				"COUNT(*) AS count",
+			).
+			From("car").
+			LeftJoin("owner ON car.owner_id = owner.id").
+			GroupBy(group.groupBy...).
+			PlaceholderFormat(sq.Dollar)
+
+		if group.limit > 0 {
+			selectBuilder = selectBuilder.
+				OrderBy("count DESC", "key").
+				Limit(group.limit)
+		} else {
+			selectBuilder = selectBuilder.OrderBy("key")
+		}
+
+		buckets, err := r.buckets(ctx, tx, r.where(ctx, selectBuilder, filter))
+		if err != nil {
+			return dto.CarStats{}, err
+		}
+
+		*group.target = buckets
+	}
+
+	for i, bucket := range stats.ByRegion {
+		if subject, ok := plate.Subject(bucket.Key); ok {
+			stats.ByRegion[i].Label = subject
+		}
+	}
+
+	return stats, nil
+}
+
+func (r Repository) buckets(
+	ctx context.Context,
+	tx *sqlx.Tx,
+	selectBuilder sq.SelectBuilder,
+) ([]dto.StatsBucket, error) {
+
+	query, args, err := selectBuilder.ToSql()
+
+	logger := r.logger.WithContext(ctx).WithFields(map[string]any{
+		"query": query,
+		"args":  args,
+	})
+
+	if err != nil {
+		logger.Warnf("can't get stats: %s", err)
+
+		return nil, errors.ErrInternal.New("can't get stats").Wrap(err)
+	}
+
+	rows := make([]statsRow, 0)
+
+	if err := tx.SelectContext(ctx, &rows, query, args...); err != nil {
+		logger.Warnf("can't get stats: %s", err)
+
+		return nil, errors.ErrInternal.New("can't get stats").Wrap(err)
+	}
+
+	buckets := make([]dto.StatsBucket, len(rows))
+	for i, row := range rows {
+		buckets[i] = dto.StatsBucket{
+			Key:   statsUnknown,
+			Label: row.Label.String,
+			Count: row.Count,
+		}
+
+		if row.Key.Valid {
+			buckets[i].Key = row.Key.String
+		}
+	}
+
+	return buckets, nil
+}
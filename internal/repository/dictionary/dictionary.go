@@ -0,0 +1,463 @@
+package dictionary
+
+import (
+	"context"
+	"database/sql"
+	sq "github.com/Masterminds/squirrel"
+	pgerr "github.com/jackc/pgerrcode"
+	"github.com/jackvonhouse/car-enrichment/internal/dto"
+	"github.com/jackvonhouse/car-enrichment/internal/errors"
+	"github.com/jackvonhouse/car-enrichment/internal/infrastructure/postgres"
+	errpkg "github.com/jackvonhouse/car-enrichment/pkg/errors"
+	"github.com/jackvonhouse/car-enrichment/pkg/log"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type Repository struct {
+	db *sqlx.DB
+
+	logger log.Logger
+}
+
+func New(
+	db *sqlx.DB,
+	logger log.Logger,
+) Repository {
+
+	return Repository{
+		db:     db,
+		logger: logger.WithField("unit", "dictionary"),
+	}
+}
+
+// exec выполняет запрос и переводит ошибки Postgres в ошибки приложения
+func (r Repository) exec(
+	ctx context.Context,
+	db sqlx.QueryerContext,
+	entity string,
+	dest any,
+	builder sq.Sqlizer,
+) error {
+
+	query, args, err := builder.ToSql()
+
+	logger := r.logger.WithContext(ctx).WithFields(map[string]any{
+		"request": map[string]any{
+			"query": query,
+			"args":  args,
+		},
+	})
+
+	if err != nil {
+		logger.Warnf("error on create sql query: %s", err)
+
+		return errors.ErrInternal.New("can't process " + entity).Wrap(err)
+	}
+
+	if err := sqlx.GetContext(ctx, db, dest, query, args...); err != nil {
+		// Промах — обычный исход, например для Normalize при каждой
+		// неизвестной марке, поэтому он не считается предупреждением
+		if errpkg.Is(err, sql.ErrNoRows) {
+			logger.Debugf("%s not found: %s", entity, err)
+
+			return errors.ErrNotFound.New(entity + " not found").Wrap(err)
+		}
+
+		if e, ok := err.(*pq.Error); ok {
+			switch e.Code {
+
+			case pgerr.UniqueViolation:
+				logger.Warnf("%s already exists: %s", entity, err)
+
+				return errors.ErrAlreadyExists.New(entity + " already exists").Wrap(err)
+
+			case pgerr.ForeignKeyViolation:
+				logger.Warnf("%s reference not found: %s", entity, err)
+
+				return errors.ErrNotFound.New(entity + " reference not found").Wrap(err)
+			}
+		}
+
+		logger.Warnf("can't process %s: %s", entity, err)
+
+		return errors.ErrInternal.New("can't process " + entity).Wrap(err)
+	}
+
+	return nil
+}
+
+func (r Repository) GetMarks(
+	ctx context.Context,
+) ([]dto.Mark, error) {
+
+	query, args, err := sq.
+		Select(
+			"mark.id",
+			"mark.name",
+			"array_remove(array_agg(mark_alias.alias ORDER BY mark_alias.alias), NULL) AS aliases",
+		).
+		From("mark").
+		LeftJoin("mark_alias ON mark_alias.mark_id = mark.id").
+		GroupBy("mark.id").
+		OrderBy("mark.name").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+
+	logger := r.logger.WithContext(ctx).WithField("query", query)
+
+	if err != nil {
+		logger.Warnf("can't get marks: %s", err)
+
+		return []dto.Mark{}, errors.ErrInternal.New("can't get marks").Wrap(err)
+	}
+
+	type mark struct {
+		ID      int64          `db:"id"`
+		Name    string         `db:"name"`
+		Aliases pq.StringArray `db:"aliases"`
+	}
+
+	rawMarks := make([]mark, 0)
+
+	if err := r.db.SelectContext(ctx, &rawMarks, query, args...); err != nil {
+		logger.Warnf("can't get marks: %s", err)
+
+		return []dto.Mark{}, errors.ErrInternal.New("can't get marks").Wrap(err)
+	}
+
+	marks := make([]dto.Mark, len(rawMarks))
+	for i, rawMark := range rawMarks {
+		marks[i] = dto.Mark{
+			ID:      rawMark.ID,
+			Name:    rawMark.Name,
+			Aliases: rawMark.Aliases,
+		}
+	}
+
+	return marks, nil
+}
+
+func (r Repository) GetModels(
+	ctx context.Context,
+	markId int64,
+) ([]dto.Model, error) {
+
+	query, args, err := sq.
+		Select(
+			"model.id",
+			"model.mark_id",
+			"model.name",
+			"array_remove(array_agg(model_alias.alias ORDER BY model_alias.alias), NULL) AS aliases",
+		).
+		From("model").
+		LeftJoin("model_alias ON model_alias.model_id = model.id").
+		Where(sq.Eq{"model.mark_id": markId}).
+		GroupBy("model.id").
+		OrderBy("model.name").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+
+	logger := r.logger.WithContext(ctx).WithFields(map[string]any{
+		"query": query,
+		"args": map[string]any{
+			"markId": markId,
+		},
+	})
+
+	if err != nil {
+		logger.Warnf("can't get models: %s", err)
+
+		return []dto.Model{}, errors.ErrInternal.New("can't get models").Wrap(err)
+	}
+
+	type model struct {
+		ID      int64          `db:"id"`
+		MarkID  int64          `db:"mark_id"`
+		Name    string         `db:"name"`
+		Aliases pq.StringArray `db:"aliases"`
+	}
+
+	rawModels := make([]model, 0)
+
+	if err := r.db.SelectContext(ctx, &rawModels, query, args...); err != nil {
+		logger.Warnf("can't get models: %s", err)
+
+		return []dto.Model{}, errors.ErrInternal.New("can't get models").Wrap(err)
+	}
+
+	models := make([]dto.Model, len(rawModels))
+	for i, rawModel := range rawModels {
+		models[i] = dto.Model{
+			ID:      rawModel.ID,
+			MarkID:  rawModel.MarkID,
+			Name:    rawModel.Name,
+			Aliases: rawModel.Aliases,
+		}
+	}
+
+	return models, nil
+}
+
+func (r Repository) CreateMark(
+	ctx context.Context,
+	create dto.CreateMark,
+) (int64, error) {
+
+	var markId int64
+
+	err := postgres.Transaction(ctx, r.db, "create mark", r.logger, func(tx *sqlx.Tx) error {
+		insertBuilder := sq.
+			Insert("mark").
+			Columns("name").
+			Values(create.Name).
+			Suffix("RETURNING id").
+			PlaceholderFormat(sq.Dollar)
+
+		if err := r.exec(ctx, tx, "mark", &markId, insertBuilder); err != nil {
+			return err
+		}
+
+		for _, alias := range create.Aliases {
+			if err := r.addMarkAlias(ctx, tx, markId, alias); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	return markId, err
+}
+
+func (r Repository) CreateModel(
+	ctx context.Context,
+	create dto.CreateModel,
+) (int64, error) {
+
+	var modelId int64
+
+	err := postgres.Transaction(ctx, r.db, "create model", r.logger, func(tx *sqlx.Tx) error {
+		insertBuilder := sq.
+			Insert("model").
+			Columns("mark_id", "name").
+			Values(create.MarkID, create.Name).
+			Suffix("RETURNING id").
+			PlaceholderFormat(sq.Dollar)
+
+		if err := r.exec(ctx, tx, "model", &modelId, insertBuilder); err != nil {
+			return err
+		}
+
+		for _, alias := range create.Aliases {
+			if err := r.addModelAlias(ctx, tx, modelId, alias); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	return modelId, err
+}
+
+func (r Repository) AddMarkAlias(
+	ctx context.Context,
+	markId int64,
+	alias string,
+) error {
+
+	return r.addMarkAlias(ctx, r.db, markId, alias)
+}
+
+func (r Repository) addMarkAlias(
+	ctx context.Context,
+	db sqlx.QueryerContext,
+	markId int64,
+	alias string,
+) error {
+
+	insertBuilder := sq.
+		Insert("mark_alias").
+		Columns("mark_id", "alias").
+		Values(markId, alias).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar)
+
+	var aliasId int64
+
+	return r.exec(ctx, db, "mark alias", &aliasId, insertBuilder)
+}
+
+func (r Repository) AddModelAlias(
+	ctx context.Context,
+	modelId int64,
+	alias string,
+) error {
+
+	return r.addModelAlias(ctx, r.db, modelId, alias)
+}
+
+func (r Repository) addModelAlias(
+	ctx context.Context,
+	db sqlx.QueryerContext,
+	modelId int64,
+	alias string,
+) error {
+
+	insertBuilder := sq.
+		Insert("model_alias").
+		Columns("model_id", "alias").
+		Values(modelId, alias).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar)
+
+	var aliasId int64
+
+	return r.exec(ctx, db, "model alias", &aliasId, insertBuilder)
+}
+
+func (r Repository) DeleteMark(
+	ctx context.Context,
+	markId int64,
+) error {
+
+	deleteBuilder := sq.
+		Delete("mark").
+		Where(sq.Eq{"id": markId}).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar)
+
+	var id int64
+
+	return r.exec(ctx, r.db, "mark", &id, deleteBuilder)
+}
+
+func (r Repository) DeleteModel(
+	ctx context.Context,
+	modelId int64,
+) error {
+
+	deleteBuilder := sq.
+		Delete("model").
+		Where(sq.Eq{"id": modelId}).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar)
+
+	var id int64
+
+	return r.exec(ctx, r.db, "model", &id, deleteBuilder)
+}
+
+func (r Repository) DeleteMarkAlias(
+	ctx context.Context,
+	markId int64,
+	alias string,
+) error {
+
+	deleteBuilder := sq.
+		Delete("mark_alias").
+		Where(sq.Eq{"mark_id": markId}).
+		Where(sq.Expr("dictionary_key(alias) = dictionary_key(?)", alias)).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar)
+
+	var id int64
+
+	return r.exec(ctx, r.db, "mark alias", &id, deleteBuilder)
+}
+
+func (r Repository) DeleteModelAlias(
+	ctx context.Context,
+	modelId int64,
+	alias string,
+) error {
+
+	deleteBuilder := sq.
+		Delete("model_alias").
+		Where(sq.Eq{"model_id": modelId}).
+		Where(sq.Expr("dictionary_key(alias) = dictionary_key(?)", alias)).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar)
+
+	var id int64
+
+	return r.exec(ctx, r.db, "model alias", &id, deleteBuilder)
+}
+
+// Normalize ищет марку и модель по названию или синониму без учёта
+// регистра, диакритики, пробелов и дефисов. Модель ищется только
+// среди моделей найденной марки
+func (r Repository) Normalize(
+	ctx context.Context,
+	mark string,
+	model string,
+) (dto.Normalized, error) {
+
+	normalized := dto.Normalized{
+		Mark:  mark,
+		Model: model,
+	}
+
+	type entry struct {
+		ID   int64  `db:"id"`
+		Name string `db:"name"`
+	}
+
+	markEntry := entry{}
+
+	markBuilder := sq.
+		Select("mark.id", "mark.name").
+		From("mark").
+		Where(sq.Or{
+			sq.Expr("dictionary_key(mark.name) = dictionary_key(?)", mark),
+			sq.Expr(
+				"mark.id IN (SELECT mark_id FROM mark_alias WHERE dictionary_key(alias) = dictionary_key(?))",
+				mark,
+			),
+		}).
+		OrderBy("mark.id").
+		Limit(1).
+		PlaceholderFormat(sq.Dollar)
+
+	if err := r.exec(ctx, r.db, "mark", &markEntry, markBuilder); err != nil {
+		if errpkg.TypeIs(err, errors.ErrNotFound) {
+			return normalized, nil
+		}
+
+		return normalized, err
+	}
+
+	normalized.MarkID = &markEntry.ID
+	normalized.Mark = markEntry.Name
+
+	modelEntry := entry{}
+
+	modelBuilder := sq.
+		Select("model.id", "model.name").
+		From("model").
+		Where(sq.Eq{"model.mark_id": markEntry.ID}).
+		Where(sq.Or{
+			sq.Expr("dictionary_key(model.name) = dictionary_key(?)", model),
+			sq.Expr(
+				"model.id IN (SELECT model_id FROM model_alias WHERE dictionary_key(alias) = dictionary_key(?))",
+				model,
+			),
+		}).
+		OrderBy("model.id").
+		Limit(1).
+		PlaceholderFormat(sq.Dollar)
+
+	if err := r.exec(ctx, r.db, "model", &modelEntry, modelBuilder); err != nil {
+		if errpkg.TypeIs(err, errors.ErrNotFound) {
+			return normalized, nil
+		}
+
+		return normalized, err
+	}
+
+	normalized.ModelID = &modelEntry.ID
+	normalized.Model = modelEntry.Name
+
+	return normalized, nil
+}
@@ -0,0 +1,75 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackvonhouse/car-enrichment/internal/dto"
+	"github.com/jackvonhouse/car-enrichment/internal/errors"
+	"github.com/jackvonhouse/car-enrichment/internal/infrastructure/postgres"
+	errpkg "github.com/jackvonhouse/car-enrichment/pkg/errors"
+	"github.com/jackvonhouse/car-enrichment/pkg/log"
+)
+
+type Repository struct {
+	storage postgres.Database
+
+	logger log.Logger
+}
+
+func New(
+	storage postgres.Database,
+	logger log.Logger,
+) Repository {
+
+	return Repository{
+		storage: storage,
+		logger:  logger.WithField("unit", "health"),
+	}
+}
+
+func (r Repository) Ping(
+	ctx context.Context,
+) error {
+
+	if err := r.storage.Ping(ctx); err != nil {
+		return errors.ErrInternal.New("can't ping database").Wrap(err)
+	}
+
+	return nil
+}
+
+// Migration читает состояние из таблицы, которую ведёт golang-migrate.
+// Без таблицы миграции считаются не применёнными
+func (r Repository) Migration(
+	ctx context.Context,
+) (dto.Migration, error) {
+
+	query, args, err := sq.
+		Select("version", "dirty").
+		From("schema_migrations").
+		Limit(1).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+
+	if err != nil {
+		r.logger.WithContext(ctx).Warn(err)
+
+		return dto.Migration{}, errors.ErrInternal.New("can't build query").Wrap(err)
+	}
+
+	migration := dto.Migration{}
+
+	err = r.storage.Database().QueryRowxContext(ctx, query, args...).
+		Scan(&migration.Version, &migration.Dirty)
+
+	if err != nil {
+		if errpkg.Is(err, sql.ErrNoRows) {
+			return dto.Migration{}, nil
+		}
+
+		return dto.Migration{}, errors.ErrInternal.New("can't get migration state").Wrap(err)
+	}
+
+	return migration, nil
+}
@@ -7,25 +7,34 @@ import (
 	pgerr "github.com/jackc/pgerrcode"
 	"github.com/jackvonhouse/car-enrichment/internal/dto"
 	"github.com/jackvonhouse/car-enrichment/internal/errors"
+	"github.com/jackvonhouse/car-enrichment/internal/infrastructure/postgres"
 	errpkg "github.com/jackvonhouse/car-enrichment/pkg/errors"
 	"github.com/jackvonhouse/car-enrichment/pkg/log"
 	"github.com/jmoiron/sqlx"
 	"github.com/lib/pq"
 )
 
+type auditRepository interface {
+	Write(context.Context, *sqlx.Tx, ...dto.CreateAudit) error
+}
+
 type Repository struct {
 	db *sqlx.DB
 
+	audit auditRepository
+
 	logger log.Logger
 }
 
 func New(
 	db *sqlx.DB,
+	audit auditRepository,
 	logger log.Logger,
 ) Repository {
 
 	return Repository{
 		db:     db,
+		audit:  audit,
 		logger: logger.WithField("unit", "car"),
 	}
 }
@@ -43,7 +52,7 @@ func (r Repository) Create(
 		PlaceholderFormat(sq.Dollar).
 		ToSql()
 
-	logger := r.logger.WithFields(map[string]any{
+	logger := r.logger.WithContext(ctx).WithFields(map[string]any{
 		"request": map[string]any{
 			"query": query,
 			"args": map[string]any{
@@ -64,31 +73,49 @@ func (r Repository) Create(
 
 	var ownerId int64
 
-	if err := r.db.GetContext(ctx, &ownerId, query, args...); err != nil {
-		if errpkg.Is(err, sql.ErrNoRows) {
-			logger.Warnf("can't create car owner: %s", err)
+	err = postgres.Transaction(ctx, r.db, "create car owner", r.logger, func(tx *sqlx.Tx) error {
+		if err := tx.GetContext(ctx, &ownerId, query, args...); err != nil {
+			if errpkg.Is(err, sql.ErrNoRows) {
+				logger.Warnf("can't create car owner: %s", err)
 
-			return 0, errors.ErrInternal.New("can't create car owner").Wrap(err)
-		}
+				return errors.ErrInternal.New("can't create car owner").Wrap(err)
+			}
 
-		if e, ok := err.(*pq.Error); ok {
-			switch e.Code {
+			if e, ok := err.(*pq.Error); ok {
+				switch e.Code {
 
-			case pgerr.UniqueViolation:
-				logger.Warnf("car owner already exists: %s", err)
+				case pgerr.UniqueViolation:
+					logger.Warnf("car owner already exists: %s", err)
 
-				return 0, errors.ErrAlreadyExists.New("car owner already exists").Wrap(err)
+					return errors.ErrAlreadyExists.New("car owner already exists").Wrap(err)
 
-			default:
-				logger.Warnf("can't create car owner: %s", err)
+				default:
+					logger.Warnf("can't create car owner: %s", err)
 
-				return 0, errors.ErrInternal.New("can't create car owner").Wrap(err)
+					return errors.ErrInternal.New("can't create car owner").Wrap(err)
+				}
 			}
+
+			logger.Warnf("can't create car owner: %s", err)
+
+			return errors.ErrInternal.New("can't create car owner").Wrap(err)
 		}
 
-		logger.Warnf("can't create car owner: %s", err)
+		return r.audit.Write(ctx, tx, dto.CreateAudit{
+			Entity:   dto.AuditEntityOwner,
+			EntityID: ownerId,
+			Action:   dto.AuditActionCreate,
+			After: dto.Owner{
+				ID:         ownerId,
+				Name:       create.Name,
+				Surname:    create.Surname,
+				Patronymic: create.Patronymic,
+			},
+		})
+	})
 
-		return 0, errors.ErrInternal.New("can't create car owner").Wrap(err)
+	if err != nil {
+		return 0, err
 	}
 
 	return ownerId, nil
@@ -100,14 +127,14 @@ func (r Repository) GetByCarId(
 ) (dto.Owner, error) {
 
 	query, args, err := sq.
-		Select("owner.*").
+		Select("owner.id", "owner.name", "owner.surname", "owner.patronymic").
 		From("car").
 		LeftJoin("owner ON car.owner_id = owner.id").
 		Where(sq.Eq{"car.id": carId}).
 		PlaceholderFormat(sq.Dollar).
 		ToSql()
 
-	logger := r.logger.WithFields(map[string]any{
+	logger := r.logger.WithContext(ctx).WithFields(map[string]any{
 		"query": query,
 		"args": map[string]any{
 			"car": map[string]any{
@@ -148,3 +175,53 @@ func (r Repository) GetByCarId(
 		Patronymic: rawOwner.Patronymic,
 	}, nil
 }
+
+// GetByName ищет владельца по полному имени,
+// по которому владелец уникален
+func (r Repository) GetByName(
+	ctx context.Context,
+	name dto.CreateOwner,
+) (dto.Owner, error) {
+
+	query, args, err := sq.
+		Select("id", "name", "surname", "patronymic").
+		From("owner").
+		Where(sq.Eq{
+			"name":       name.Name,
+			"surname":    name.Surname,
+			"patronymic": name.Patronymic,
+		}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+
+	logger := r.logger.WithContext(ctx).WithFields(map[string]any{
+		"query": query,
+		"args": map[string]any{
+			"owner": map[string]any{
+				"name":       name.Name,
+				"surname":    name.Surname,
+				"patronymic": name.Patronymic,
+			},
+		},
+	})
+
+	if err != nil {
+		logger.Warnf("can't get owner: %s", err)
+
+		return dto.Owner{}, errors.ErrInternal.New("can't get owner").Wrap(err)
+	}
+
+	owner := dto.Owner{}
+
+	if err := r.db.GetContext(ctx, &owner, query, args...); err != nil {
+		logger.Warnf("can't get owner: %s", err)
+
+		if !errpkg.Is(err, sql.ErrNoRows) {
+			return dto.Owner{}, errors.ErrInternal.New("can't get owner").Wrap(err)
+		}
+
+		return dto.Owner{}, errors.ErrNotFound.New("owner not found").Wrap(err)
+	}
+
+	return owner, nil
+}
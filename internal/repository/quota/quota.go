@@ -0,0 +1,84 @@
+package quota
+
+import (
+	"context"
+	"database/sql"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackvonhouse/car-enrichment/internal/errors"
+	errpkg "github.com/jackvonhouse/car-enrichment/pkg/errors"
+	"github.com/jackvonhouse/car-enrichment/pkg/log"
+	"github.com/jmoiron/sqlx"
+	"time"
+)
+
+type Repository struct {
+	db *sqlx.DB
+
+	logger log.Logger
+}
+
+func New(
+	db *sqlx.DB,
+	logger log.Logger,
+) Repository {
+
+	return Repository{
+		db:     db,
+		logger: logger.WithField("unit", "quota"),
+	}
+}
+
+// Consume списывает amount из суточной квоты клиента одним запросом,
+// поэтому параллельные запросы не превышают limit. Если квоты
+// не хватает, ничего не списывается
+func (r Repository) Consume(
+	ctx context.Context,
+	client string,
+	day time.Time,
+	amount int,
+	limit int,
+) (int, error) {
+
+	query, args, err := sq.
+		Insert("enrichment_quota").
+		Columns("client", "day", "used").
+		Values(client, day.Format(time.DateOnly), amount).
+		Suffix(
+			"ON CONFLICT (client, day) DO UPDATE SET used = enrichment_quota.used + EXCLUDED.used "+
+				"WHERE enrichment_quota.used + EXCLUDED.used <= ? RETURNING used",
+			limit,
+		).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+
+	logger := r.logger.WithContext(ctx).WithFields(map[string]any{
+		"query": query,
+		"args": map[string]any{
+			"client": client,
+			"amount": amount,
+			"limit":  limit,
+		},
+	})
+
+	if err != nil {
+		logger.Warnf("error on consume sql query: %s", err)
+
+		return 0, errors.ErrInternal.New("can't consume quota").Wrap(err)
+	}
+
+	var used int
+
+	if err := r.db.GetContext(ctx, &used, query, args...); err != nil {
+		if errpkg.Is(err, sql.ErrNoRows) {
+			logger.Infof("daily enrichment quota exceeded")
+
+			return 0, errors.ErrLimitExceeded.New("daily enrichment quota exceeded").Wrap(err)
+		}
+
+		logger.Warnf("can't consume quota: %s", err)
+
+		return 0, errors.ErrInternal.New("can't consume quota").Wrap(err)
+	}
+
+	return used, nil
+}
@@ -0,0 +1,26 @@
+package requestid
+
+import "context"
+
+// Header — заголовок, в котором идентификатор запроса
+// принимается от клиента и возвращается в ответе
+const Header = "X-Request-ID"
+
+type contextKey struct{}
+
+func With(
+	ctx context.Context,
+	id string,
+) context.Context {
+
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+func FromContext(
+	ctx context.Context,
+) string {
+
+	id, _ := ctx.Value(contextKey{}).(string)
+
+	return id
+}
@@ -0,0 +1,172 @@
+package apikey
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"github.com/jackvonhouse/car-enrichment/config"
+	"github.com/jackvonhouse/car-enrichment/internal/dto"
+	"github.com/jackvonhouse/car-enrichment/internal/errors"
+	"github.com/jackvonhouse/car-enrichment/internal/identity"
+	errpkg "github.com/jackvonhouse/car-enrichment/pkg/errors"
+	"github.com/jackvonhouse/car-enrichment/pkg/log"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// Ключи узнаются по приставке в журналах и конфигурации
+	keyPrefix = "ce_"
+	keyBytes  = 32
+	// Символов ключа, которые хранятся открыто для списка ключей
+	keyVisible = len(keyPrefix) + 8
+
+	// BootstrapName — имя клиента с ключом из конфигурации
+	BootstrapName = "bootstrap"
+)
+
+type apiKeyRepository interface {
+	Create(context.Context, dto.CreateAPIKey) (dto.APIKey, error)
+
+	Get(context.Context) ([]dto.APIKey, error)
+	GetByHash(context.Context, string) (dto.APIKey, error)
+
+	Revoke(context.Context, int64) error
+}
+
+type Service struct {
+	apiKey apiKeyRepository
+
+	bootstrapHash []byte
+
+	logger log.Logger
+}
+
+func New(
+	apiKey apiKeyRepository,
+	config config.Auth,
+	logger log.Logger,
+) Service {
+
+	s := Service{
+		apiKey: apiKey,
+		logger: logger.WithField("unit", "api key"),
+	}
+
+	if config.BootstrapKey != "" {
+		hash := sha256.Sum256([]byte(config.BootstrapKey))
+		s.bootstrapHash = hash[:]
+	}
+
+	return s
+}
+
+func hash(
+	key string,
+) string {
+
+	sum := sha256.Sum256([]byte(key))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// Issue выдаёт новый ключ. Сам ключ возвращается только здесь,
+// в базе остаются его хэш и начало
+func (s Service) Issue(
+	ctx context.Context,
+	create dto.CreateAPIKey,
+) (dto.IssuedAPIKey, error) {
+
+	create.Name = strings.TrimSpace(create.Name)
+
+	scopes := make([]identity.Scope, 0, len(create.Scopes))
+	for _, scope := range create.Scopes {
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	create.Scopes = scopes
+
+	raw := make([]byte, keyBytes)
+	if _, err := rand.Read(raw); err != nil {
+		s.logger.WithContext(ctx).Warnf("can't generate api key: %s", err)
+
+		return dto.IssuedAPIKey{}, errors.ErrInternal.New("can't generate api key").Wrap(err)
+	}
+
+	key := keyPrefix + base64.RawURLEncoding.EncodeToString(raw)
+
+	create.Prefix = key[:keyVisible]
+	create.Hash = hash(key)
+
+	created, err := s.apiKey.Create(ctx, create)
+	if err != nil {
+		return dto.IssuedAPIKey{}, err
+	}
+
+	return dto.IssuedAPIKey{
+		APIKey: created,
+		Key:    key,
+	}, nil
+}
+
+func (s Service) Get(
+	ctx context.Context,
+) ([]dto.APIKey, error) {
+
+	return s.apiKey.Get(ctx)
+}
+
+func (s Service) Revoke(
+	ctx context.Context,
+	keyId int64,
+) error {
+
+	return s.apiKey.Revoke(ctx, keyId)
+}
+
+// Authenticate возвращает клиента по ключу. Неизвестный, отозванный
+// и истёкший ключи не различаются в ответе, причина остаётся в журнале
+func (s Service) Authenticate(
+	ctx context.Context,
+	key string,
+) (identity.Identity, error) {
+
+	if s.bootstrapHash != nil {
+		sum := sha256.Sum256([]byte(key))
+
+		if subtle.ConstantTimeCompare(sum[:], s.bootstrapHash) == 1 {
+			return identity.Identity{
+				Name:   BootstrapName,
+				Scopes: []identity.Scope{identity.ScopeAdmin},
+			}, nil
+		}
+	}
+
+	found, err := s.apiKey.GetByHash(ctx, hash(key))
+	if err != nil {
+		if errpkg.TypeIs(err, errors.ErrNotFound) {
+			s.logger.WithContext(ctx).Infof("unknown api key %s...", key[:min(len(key), keyVisible)])
+
+			return identity.Identity{}, errors.ErrUnauthorized.New("invalid api key").Wrap(err)
+		}
+
+		return identity.Identity{}, err
+	}
+
+	if !found.Active(time.Now()) {
+		s.logger.WithContext(ctx).Infof("api key %q (%s...) is expired or revoked", found.Name, found.Prefix)
+
+		return identity.Identity{}, errors.ErrUnauthorized.New("invalid api key")
+	}
+
+	return identity.Identity{
+		Name:   found.Name,
+		Scopes: found.Scopes,
+	}, nil
+}
@@ -0,0 +1,37 @@
+package audit
+
+import (
+	"context"
+	"github.com/jackvonhouse/car-enrichment/internal/dto"
+	"github.com/jackvonhouse/car-enrichment/pkg/log"
+)
+
+type auditRepository interface {
+	Get(context.Context, dto.AuditFilter, dto.Pagination) ([]dto.Audit, error)
+}
+
+type Service struct {
+	audit auditRepository
+
+	logger log.Logger
+}
+
+func New(
+	audit auditRepository,
+	logger log.Logger,
+) Service {
+
+	return Service{
+		audit:  audit,
+		logger: logger.WithField("unit", "audit"),
+	}
+}
+
+func (s Service) Get(
+	ctx context.Context,
+	filter dto.AuditFilter,
+	pagination dto.Pagination,
+) ([]dto.Audit, error) {
+
+	return s.audit.Get(ctx, filter, pagination)
+}
@@ -2,35 +2,59 @@ package car
 
 import (
 	"context"
+	"encoding/json"
+	"github.com/jackvonhouse/car-enrichment/config"
 	"github.com/jackvonhouse/car-enrichment/internal/dto"
+	"github.com/jackvonhouse/car-enrichment/pkg/cache"
 	"github.com/jackvonhouse/car-enrichment/pkg/log"
+	"github.com/jackvonhouse/car-enrichment/pkg/plate"
+	"time"
 )
 
 type carRepository interface {
 	Create(context.Context, map[int64]dto.Car) error
 
-	Get(context.Context, dto.Filter, dto.Pagination) ([]dto.Car, error)
+	Get(context.Context, dto.Filter, dto.Pagination) (dto.CarList, error)
 	GetById(context.Context, int64) (dto.Car, error)
 
+	Search(context.Context, string, dto.Pagination) ([]dto.SearchResult, error)
+
+	Stats(context.Context, dto.Filter, dto.StatsOptions) (dto.CarStats, error)
+
+	Iterate(context.Context, dto.Filter, []dto.Sort, func(dto.Car) error) error
+
 	Update(context.Context, dto.Car) error
 
 	Delete(context.Context, dto.Car) error
 }
 
+type carMetrics interface {
+	CarsCreated(int)
+	CarUpdated()
+}
+
 type Service struct {
-	car carRepository
+	car     carRepository
+	metrics carMetrics
+
+	// stats кэширует результаты агрегаций, ключ — фильтр и параметры
+	stats *cache.Cache[string, dto.CarStats]
 
 	logger log.Logger
 }
 
 func New(
 	car carRepository,
+	metrics carMetrics,
+	config config.Stats,
 	logger log.Logger,
 ) Service {
 
 	return Service{
-		car:    car,
-		logger: logger.WithField("unit", "car"),
+		car:     car,
+		metrics: metrics,
+		stats:   cache.New[string, dto.CarStats](config.CacheTTL),
+		logger:  logger.WithField("unit", "car"),
 	}
 }
 
@@ -39,18 +63,104 @@ func (s Service) Create(
 	cars map[int64]dto.Car,
 ) error {
 
-	return s.car.Create(ctx, cars)
+	for id, car := range cars {
+		car.Region = s.region(ctx, car.RegNum)
+		cars[id] = car
+	}
+
+	if err := s.car.Create(ctx, cars); err != nil {
+		return err
+	}
+
+	s.metrics.CarsCreated(len(cars))
+
+	return nil
+}
+
+// region определяет регион по номеру. Регион не задаётся клиентом
+// и меняется только вместе с номером
+func (s Service) region(
+	ctx context.Context,
+	regNum string,
+) *dto.Region {
+
+	p, err := plate.Parse(regNum)
+	if err != nil {
+		s.logger.WithContext(ctx).Infof("can't parse region from %s: %s", regNum, err)
+
+		return nil
+	}
+
+	subject, _ := plate.Subject(p.Region)
+
+	return &dto.Region{
+		Code:    p.Region,
+		Subject: subject,
+	}
 }
 
 func (s Service) Get(
 	ctx context.Context,
 	filter dto.Filter,
 	pagination dto.Pagination,
-) ([]dto.Car, error) {
+) (dto.CarList, error) {
 
 	return s.car.Get(ctx, filter, pagination)
 }
 
+func (s Service) Search(
+	ctx context.Context,
+	query string,
+	pagination dto.Pagination,
+) ([]dto.SearchResult, error) {
+
+	return s.car.Search(ctx, query, pagination)
+}
+
+// Stats отдаёт статистику из кэша, если такой же запрос
+// выполнялся недавно, иначе считает её заново
+func (s Service) Stats(
+	ctx context.Context,
+	filter dto.Filter,
+	options dto.StatsOptions,
+) (dto.CarStats, error) {
+
+	key, err := json.Marshal(map[string]any{
+		"filter":  filter,
+		"options": options,
+	})
+	if err != nil {
+		s.logger.WithContext(ctx).Warnf("can't build stats cache key: %s", err)
+
+		return s.car.Stats(ctx, filter, options)
+	}
+
+	if stats, ok := s.stats.Get(string(key)); ok {
+		s.logger.WithContext(ctx).Debug("stats found in cache")
+
+		return stats, nil
+	}
+
+	stats, err := s.car.Stats(ctx, filter, options)
+	if err != nil {
+		return dto.CarStats{}, err
+	}
+
+	s.stats.Set(string(key), stats)
+
+	return stats, nil
+}
+
+func (s Service) Iterate(
+	ctx context.Context,
+	filter dto.Filter,
+	sort []dto.Sort,
+	fn func(dto.Car) error,
+) error {
+
+	return s.car.Iterate(ctx, filter, sort, fn)
+}
+
 func (s Service) GetById(
 	ctx context.Context,
 	id int64,
@@ -66,14 +176,30 @@ func (s Service) Update(
 
 	_, err := s.car.GetById(ctx, update.ID)
 	if err != nil {
-		s.logger.Infof("can't get car by id (%d): %s", update.ID, err)
+		s.logger.WithContext(ctx).Infof("can't get car by id (%d): %s", update.ID, err)
+
+		return err
+	}
+
+	s.logger.WithContext(ctx).Debug("car found")
+
+	update.Region = nil
+	if update.RegNum != "" {
+		update.Region = s.region(ctx, update.RegNum)
+	}
+
+	if update.Mileage != nil && update.MileageAt == nil {
+		now := time.Now()
+		update.MileageAt = &now
+	}
 
+	if err := s.car.Update(ctx, update); err != nil {
 		return err
 	}
 
-	s.logger.Debug("car found")
+	s.metrics.CarUpdated()
 
-	return s.car.Update(ctx, update)
+	return nil
 }
 
 func (s Service) Delete(
@@ -83,12 +209,12 @@ func (s Service) Delete(
 
 	car, err := s.car.GetById(ctx, carId)
 	if err != nil {
-		s.logger.Infof("can't get car by id (%d): %s", carId, err)
+		s.logger.WithContext(ctx).Infof("can't get car by id (%d): %s", carId, err)
 
 		return err
 	}
 
-	s.logger.Debug("car found")
+	s.logger.WithContext(ctx).Debug("car found")
 
 	return s.car.Delete(ctx, car)
 }
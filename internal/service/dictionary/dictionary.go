@@ -0,0 +1,170 @@
+package dictionary
+
+import (
+	"context"
+	"github.com/jackvonhouse/car-enrichment/internal/dto"
+	"github.com/jackvonhouse/car-enrichment/pkg/log"
+	"strings"
+)
+
+type dictionaryRepository interface {
+	GetMarks(context.Context) ([]dto.Mark, error)
+	GetModels(context.Context, int64) ([]dto.Model, error)
+
+	CreateMark(context.Context, dto.CreateMark) (int64, error)
+	CreateModel(context.Context, dto.CreateModel) (int64, error)
+
+	AddMarkAlias(context.Context, int64, string) error
+	AddModelAlias(context.Context, int64, string) error
+
+	DeleteMark(context.Context, int64) error
+	DeleteModel(context.Context, int64) error
+
+	DeleteMarkAlias(context.Context, int64, string) error
+	DeleteModelAlias(context.Context, int64, string) error
+
+	Normalize(context.Context, string, string) (dto.Normalized, error)
+}
+
+type Service struct {
+	dictionary dictionaryRepository
+
+	logger log.Logger
+}
+
+func New(
+	dictionary dictionaryRepository,
+	logger log.Logger,
+) Service {
+
+	return Service{
+		dictionary: dictionary,
+		logger:     logger.WithField("unit", "dictionary"),
+	}
+}
+
+func (s Service) GetMarks(
+	ctx context.Context,
+) ([]dto.Mark, error) {
+
+	return s.dictionary.GetMarks(ctx)
+}
+
+func (s Service) GetModels(
+	ctx context.Context,
+	markId int64,
+) ([]dto.Model, error) {
+
+	return s.dictionary.GetModels(ctx, markId)
+}
+
+func (s Service) CreateMark(
+	ctx context.Context,
+	create dto.CreateMark,
+) (int64, error) {
+
+	create.Name = strings.TrimSpace(create.Name)
+	create.Aliases = s.aliases(create.Name, create.Aliases)
+
+	return s.dictionary.CreateMark(ctx, create)
+}
+
+func (s Service) CreateModel(
+	ctx context.Context,
+	create dto.CreateModel,
+) (int64, error) {
+
+	create.Name = strings.TrimSpace(create.Name)
+	create.Aliases = s.aliases(create.Name, create.Aliases)
+
+	return s.dictionary.CreateModel(ctx, create)
+}
+
+// aliases убирает пустые синонимы и повторы, в том числе самого названия,
+// которое и так участвует в сопоставлении
+func (s Service) aliases(
+	name string,
+	aliases []string,
+) []string {
+
+	seen := map[string]struct{}{
+		strings.ToLower(name): {},
+	}
+
+	unique := make([]string, 0, len(aliases))
+
+	for _, alias := range aliases {
+		alias = strings.TrimSpace(alias)
+		key := strings.ToLower(alias)
+
+		if _, ok := seen[key]; ok || alias == "" {
+			continue
+		}
+
+		seen[key] = struct{}{}
+		unique = append(unique, alias)
+	}
+
+	return unique
+}
+
+func (s Service) AddMarkAlias(
+	ctx context.Context,
+	markId int64,
+	alias string,
+) error {
+
+	return s.dictionary.AddMarkAlias(ctx, markId, strings.TrimSpace(alias))
+}
+
+func (s Service) AddModelAlias(
+	ctx context.Context,
+	modelId int64,
+	alias string,
+) error {
+
+	return s.dictionary.AddModelAlias(ctx, modelId, strings.TrimSpace(alias))
+}
+
+func (s Service) DeleteMark(
+	ctx context.Context,
+	markId int64,
+) error {
+
+	return s.dictionary.DeleteMark(ctx, markId)
+}
+
+func (s Service) DeleteModel(
+	ctx context.Context,
+	modelId int64,
+) error {
+
+	return s.dictionary.DeleteModel(ctx, modelId)
+}
+
+func (s Service) DeleteMarkAlias(
+	ctx context.Context,
+	markId int64,
+	alias string,
+) error {
+
+	return s.dictionary.DeleteMarkAlias(ctx, markId, alias)
+}
+
+func (s Service) DeleteModelAlias(
+	ctx context.Context,
+	modelId int64,
+	alias string,
+) error {
+
+	return s.dictionary.DeleteModelAlias(ctx, modelId, alias)
+}
+
+func (s Service) Normalize(
+	ctx context.Context,
+	mark string,
+	model string,
+) (dto.Normalized, error) {
+
+	return s.dictionary.Normalize(ctx, strings.TrimSpace(mark), strings.TrimSpace(model))
+}
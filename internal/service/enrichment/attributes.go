@@ -0,0 +1,133 @@
+package enrichment
+
+import (
+	"context"
+	"github.com/jackvonhouse/car-enrichment/internal/dto"
+	"math"
+	"strings"
+	"time"
+)
+
+// Внешний API возвращает типы кузова и топлива в свободной форме,
+// неизвестные значения сохраняются как null
+var (
+	bodyTypes = map[string]dto.BodyType{
+		"sedan":       dto.BodyTypeSedan,
+		"седан":       dto.BodyTypeSedan,
+		"hatchback":   dto.BodyTypeHatchback,
+		"хэтчбек":     dto.BodyTypeHatchback,
+		"хетчбэк":     dto.BodyTypeHatchback,
+		"liftback":    dto.BodyTypeLiftback,
+		"лифтбек":     dto.BodyTypeLiftback,
+		"wagon":       dto.BodyTypeWagon,
+		"estate":      dto.BodyTypeWagon,
+		"универсал":   dto.BodyTypeWagon,
+		"suv":         dto.BodyTypeSUV,
+		"crossover":   dto.BodyTypeSUV,
+		"внедорожник": dto.BodyTypeSUV,
+		"кроссовер":   dto.BodyTypeSUV,
+		"coupe":       dto.BodyTypeCoupe,
+		"купе":        dto.BodyTypeCoupe,
+		"convertible": dto.BodyTypeConvertible,
+		"cabriolet":   dto.BodyTypeConvertible,
+		"кабриолет":   dto.BodyTypeConvertible,
+		"minivan":     dto.BodyTypeMinivan,
+		"минивэн":     dto.BodyTypeMinivan,
+		"pickup":      dto.BodyTypePickup,
+		"пикап":       dto.BodyTypePickup,
+		"van":         dto.BodyTypeVan,
+		"фургон":      dto.BodyTypeVan,
+	}
+
+	fuelTypes = map[string]dto.FuelType{
+		"petrol":   dto.FuelTypePetrol,
+		"gasoline": dto.FuelTypePetrol,
+		"бензин":   dto.FuelTypePetrol,
+		"diesel":   dto.FuelTypeDiesel,
+		"дизель":   dto.FuelTypeDiesel,
+		"gas":      dto.FuelTypeGas,
+		"lpg":      dto.FuelTypeGas,
+		"cng":      dto.FuelTypeGas,
+		"газ":      dto.FuelTypeGas,
+		"hybrid":   dto.FuelTypeHybrid,
+		"гибрид":   dto.FuelTypeHybrid,
+		"electric": dto.FuelTypeElectric,
+		"ev":       dto.FuelTypeElectric,
+		"электро":  dto.FuelTypeElectric,
+	}
+)
+
+// Объём больше этого значения считается указанным в см³, иначе в литрах
+const maxEngineLiters = 20
+
+func (e Service) attributes(
+	ctx context.Context,
+	info carInfo,
+	car *dto.Car,
+) {
+
+	if color := strings.TrimSpace(info.Color); color != "" {
+		car.Color = &color
+	}
+
+	if info.BodyType != "" {
+		if bodyType, ok := bodyTypes[strings.ToLower(strings.TrimSpace(info.BodyType))]; ok {
+			car.BodyType = &bodyType
+		} else {
+			e.logger.WithContext(ctx).Infof("unknown body type %s for %s", info.BodyType, info.RegNum)
+		}
+	}
+
+	if info.FuelType != "" {
+		if fuelType, ok := fuelTypes[strings.ToLower(strings.TrimSpace(info.FuelType))]; ok {
+			car.FuelType = &fuelType
+		} else {
+			e.logger.WithContext(ctx).Infof("unknown fuel type %s for %s", info.FuelType, info.RegNum)
+		}
+	}
+
+	if info.EngineVolume > 0 {
+		volume := info.EngineVolume
+		if volume <= maxEngineLiters {
+			volume *= 1000
+		}
+
+		cm3 := int(math.Round(volume))
+		car.EngineVolume = &cm3
+	}
+
+	if info.Power > 0 {
+		power := info.Power
+		car.Power = &power
+	}
+
+	if info.Mileage != nil && *info.Mileage >= 0 {
+		mileageAt := time.Now()
+
+		if info.MileageAt != "" {
+			parsed, err := e.parseDate(info.MileageAt)
+			if err != nil {
+				e.logger.WithContext(ctx).Infof("invalid mileage date %s for %s: %s", info.MileageAt, info.RegNum, err)
+
+				return
+			}
+
+			mileageAt = parsed
+		}
+
+		mileage := *info.Mileage
+		car.Mileage = &mileage
+		car.MileageAt = &mileageAt
+	}
+}
+
+func (e Service) parseDate(
+	value string,
+) (time.Time, error) {
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	return time.Parse(time.DateOnly, value)
+}
@@ -3,63 +3,96 @@ package enrichment
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"github.com/jackvonhouse/car-enrichment/config"
 	"github.com/jackvonhouse/car-enrichment/internal/dto"
 	"github.com/jackvonhouse/car-enrichment/pkg/log"
+	"github.com/jackvonhouse/car-enrichment/pkg/safe"
+	"github.com/jackvonhouse/car-enrichment/pkg/vin"
 	"net/http"
+	"net/url"
 	"sync"
 	"time"
 )
 
+type enrichmentMetrics interface {
+	EnrichmentAttempt(string, error, time.Duration)
+	EnrichmentRetry(string)
+	EnrichmentResult(string, bool)
+}
+
 type Service struct {
 	config config.API
 
+	// provider — хост внешнего API, метка метрик
+	provider string
+	metrics  enrichmentMetrics
+
 	logger log.Logger
 }
 
 func New(
 	config config.API,
+	metrics enrichmentMetrics,
 	logger log.Logger,
 ) Service {
 
+	provider := config.Url
+	if u, err := url.Parse(config.Url); err == nil && u.Host != "" {
+		provider = u.Host
+	}
+
 	return Service{
-		config: config,
-		logger: logger.WithField("unit", "enrichment"),
+		config:   config,
+		provider: provider,
+		metrics:  metrics,
+		logger:   logger.WithField("unit", "enrichment"),
 	}
 }
 
 func (e Service) Enrichment(
-	_ context.Context,
+	ctx context.Context,
 	regNumbers []string,
 ) (map[int64]dto.Car, error) {
 
 	const maxAttempts = 3
 
-	e.logger.Debugf("starting enrichment for %d cars", len(regNumbers))
-	e.logger.Debugf("max attempts: %d", maxAttempts)
+	e.logger.WithContext(ctx).Debugf("starting enrichment for %d cars", len(regNumbers))
+	e.logger.WithContext(ctx).Debugf("max attempts: %d", maxAttempts)
 
 	cars := make(map[int64]dto.Car)
 
 	m := &sync.Mutex{}
 	wg := &sync.WaitGroup{}
-	wg.Add(len(regNumbers))
 
 	for i, regNumber := range regNumbers {
-		e.logger.Debugf("starting enrichment for car with regNum %s", regNumber)
+		e.logger.WithContext(ctx).Debugf("starting enrichment for car with regNum %s", regNumber)
 
 		i := int64(i)
 
-		go func(i int64, regNumber string) {
-			defer wg.Done()
-
+		// Паника при разборе ответа оставляет номер необогащённым,
+		// остальные номера обрабатываются как обычно
+		safe.Go(wg, func() error {
 			var (
 				car dto.Car
 				err error
 			)
 
 			for attempt := 0; attempt < maxAttempts; attempt++ {
-				car, err = e.makeRequest(regNumber)
+				if attempt > 0 {
+					e.metrics.EnrichmentRetry(e.provider)
+				}
+
+				start := time.Now()
+
+				car, err = e.makeRequest(ctx, regNumber)
+				e.metrics.EnrichmentAttempt(e.provider, err, time.Since(start))
+
 				if err == nil {
+					// Внешний API может вернуть номер в другом написании,
+					// сохраняем канонический номер из запроса
+					car.RegNum = regNumber
+
 					m.Lock()
 					cars[i] = car
 					m.Unlock()
@@ -67,7 +100,17 @@ func (e Service) Enrichment(
 					break
 				}
 			}
-		}(i, regNumber)
+
+			e.metrics.EnrichmentResult(e.provider, err == nil)
+
+			return nil
+		}, func(err error) {
+			e.metrics.EnrichmentResult(e.provider, false)
+
+			e.logger.WithContext(ctx).
+				WithField("stack", safe.Stack(err)).
+				Errorf("enrichment of %s failed: %s", regNumber, err)
+		})
 	}
 
 	wg.Wait()
@@ -75,7 +118,37 @@ func (e Service) Enrichment(
 	return cars, nil
 }
 
+// Probe проверяет, что внешний API отвечает. Любой ответ, кроме 5xx,
+// означает, что API доступен: запрос без номера может быть отклонён
+func (e Service) Probe(
+	ctx context.Context,
+) error {
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, e.config.Url, nil)
+	if err != nil {
+		return err
+	}
+
+	client := &http.Client{
+		Timeout: 1 * time.Second,
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("enrichment api responded with %s", response.Status)
+	}
+
+	return nil
+}
+
 func (e Service) makeRequest(
+	ctx context.Context,
 	regNumber string,
 ) (dto.Car, error) {
 
@@ -99,11 +172,69 @@ func (e Service) makeRequest(
 
 	defer response.Body.Close()
 
-	car := dto.Car{}
+	info := carInfo{}
 
-	if err := json.NewDecoder(response.Body).Decode(&car); err != nil {
+	if err := json.NewDecoder(response.Body).Decode(&info); err != nil {
 		return dto.Car{}, err
 	}
 
-	return car, nil
+	return e.car(ctx, info), nil
+}
+
+// carInfo — ответ внешнего API
+type carInfo struct {
+	RegNum string `json:"regNum"`
+	Mark   string `json:"mark"`
+	Model  string `json:"model"`
+	Year   int    `json:"year"`
+	VIN    string `json:"vin"`
+
+	Color    string `json:"color"`
+	BodyType string `json:"bodyType"`
+	FuelType string `json:"fuelType"`
+	// EngineVolume приходит в литрах или в см³
+	EngineVolume float64 `json:"engineVolume"`
+	Power        int     `json:"power"`
+	Mileage      *int    `json:"mileage"`
+	MileageAt    string  `json:"mileageAt"`
+
+	Owner struct {
+		Name       string `json:"name"`
+		Surname    string `json:"surname"`
+		Patronymic string `json:"patronymic"`
+	} `json:"owner"`
+}
+
+func (e Service) car(
+	ctx context.Context,
+	info carInfo,
+) dto.Car {
+
+	car := dto.Car{
+		RegNum: info.RegNum,
+		Mark:   info.Mark,
+		Model:  info.Model,
+		Year:   info.Year,
+		Owner: dto.Owner{
+			Name:       info.Owner.Name,
+			Surname:    info.Owner.Surname,
+			Patronymic: info.Owner.Patronymic,
+		},
+	}
+
+	e.attributes(ctx, info, &car)
+
+	// Некорректный VIN не мешает сохранить остальные данные
+	if info.VIN != "" {
+		v, err := vin.Parse(info.VIN)
+		if err != nil {
+			e.logger.WithContext(ctx).Infof("invalid vin %s for %s: %s", info.VIN, info.RegNum, err)
+
+			return car
+		}
+
+		car.VIN = &v.Value
+	}
+
+	return car
 }
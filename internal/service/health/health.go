@@ -0,0 +1,180 @@
+package health
+
+import (
+	"context"
+	"github.com/jackvonhouse/car-enrichment/config"
+	"github.com/jackvonhouse/car-enrichment/internal/dto"
+	"github.com/jackvonhouse/car-enrichment/migration"
+	"github.com/jackvonhouse/car-enrichment/pkg/cache"
+	"github.com/jackvonhouse/car-enrichment/pkg/log"
+	"time"
+)
+
+const (
+	checkDatabase   = "database"
+	checkMigrations = "migrations"
+	checkEnrichment = "enrichment"
+	checkShutdown   = "shutdown"
+)
+
+type healthRepository interface {
+	Ping(context.Context) error
+	Migration(context.Context) (dto.Migration, error)
+}
+
+type enrichmentService interface {
+	Probe(context.Context) error
+}
+
+type shutdownState interface {
+	Stopping() bool
+}
+
+type Service struct {
+	health     healthRepository
+	enrichment enrichmentService
+	shutdown   shutdownState
+
+	// probes хранит результат проверки внешнего API под одним ключом
+	probes *cache.Cache[string, dto.HealthCheck]
+
+	logger log.Logger
+}
+
+func New(
+	health healthRepository,
+	enrichment enrichmentService,
+	shutdown shutdownState,
+	config config.Health,
+	logger log.Logger,
+) Service {
+
+	return Service{
+		health:     health,
+		enrichment: enrichment,
+		shutdown:   shutdown,
+		probes:     cache.New[string, dto.HealthCheck](config.ProbeTTL),
+		logger:     logger.WithField("unit", "health"),
+	}
+}
+
+// Live сообщает, что процесс работает и обрабатывает запросы
+func (s Service) Live(
+	_ context.Context,
+) dto.Health {
+
+	return dto.Health{Status: dto.HealthStatusOk}
+}
+
+// Ready проверяет зависимости сервиса. Сервис не готов, если недоступна
+// база, миграции не применены до конца, внешний API не отвечает
+// или началась остановка
+func (s Service) Ready(
+	ctx context.Context,
+) dto.Health {
+
+	checks := map[string]dto.HealthCheck{
+		checkDatabase:   s.check(ctx, s.health.Ping),
+		checkMigrations: s.migrations(ctx),
+		checkEnrichment: s.probeEnrichment(ctx),
+		checkShutdown:   s.stopping(),
+	}
+
+	status := dto.HealthStatusOk
+
+	for name, check := range checks {
+		if check.Status != dto.HealthStatusOk {
+			s.logger.WithContext(ctx).Warnf("%s is not ready: %s", name, check.Error)
+
+			status = dto.HealthStatusFail
+		}
+	}
+
+	return dto.Health{
+		Status: status,
+		Checks: checks,
+	}
+}
+
+func (s Service) check(
+	ctx context.Context,
+	probe func(context.Context) error,
+) dto.HealthCheck {
+
+	start := time.Now()
+	err := probe(ctx)
+
+	check := dto.HealthCheck{
+		Status:    dto.HealthStatusOk,
+		LatencyMs: time.Since(start).Milliseconds(),
+	}
+
+	if err != nil {
+		check.Status = dto.HealthStatusFail
+		check.Error = err.Error()
+	}
+
+	return check
+}
+
+func (s Service) migrations(
+	ctx context.Context,
+) dto.HealthCheck {
+
+	var state dto.Migration
+
+	check := s.check(ctx, func(ctx context.Context) error {
+		var err error
+		state, err = s.health.Migration(ctx)
+
+		return err
+	})
+
+	if check.Status != dto.HealthStatusOk {
+		return check
+	}
+
+	latest := migration.Latest()
+
+	check.Details = map[string]any{
+		"version":  state.Version,
+		"expected": latest,
+		"dirty":    state.Dirty,
+	}
+
+	switch {
+	case state.Dirty:
+		check.Status, check.Error = dto.HealthStatusFail, "migration is dirty"
+
+	case state.Version < latest:
+		check.Status, check.Error = dto.HealthStatusFail, "migrations are not applied"
+	}
+
+	return check
+}
+
+// probeEnrichment не обращается к внешнему API чаще раза в ProbeTTL
+func (s Service) probeEnrichment(
+	ctx context.Context,
+) dto.HealthCheck {
+
+	if check, ok := s.probes.Get(checkEnrichment); ok {
+		return check
+	}
+
+	check := s.check(ctx, s.enrichment.Probe)
+	s.probes.Set(checkEnrichment, check)
+
+	return check
+}
+
+func (s Service) stopping() dto.HealthCheck {
+	if s.shutdown.Stopping() {
+		return dto.HealthCheck{
+			Status: dto.HealthStatusFail,
+			Error:  "service is shutting down",
+		}
+	}
+
+	return dto.HealthCheck{Status: dto.HealthStatusOk}
+}
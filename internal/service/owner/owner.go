@@ -10,6 +10,7 @@ type ownerRepository interface {
 	Create(context.Context, dto.CreateOwner) (int64, error)
 
 	GetByCarId(context.Context, int64) (dto.Owner, error)
+	GetByName(context.Context, dto.CreateOwner) (dto.Owner, error)
 }
 
 type Service struct {
@@ -44,3 +45,11 @@ func (s Service) GetByCarId(
 
 	return s.owner.GetByCarId(ctx, carId)
 }
+
+func (s Service) GetByName(
+	ctx context.Context,
+	name dto.CreateOwner,
+) (dto.Owner, error) {
+
+	return s.owner.GetByName(ctx, name)
+}
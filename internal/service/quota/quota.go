@@ -0,0 +1,67 @@
+package quota
+
+import (
+	"context"
+	"github.com/jackvonhouse/car-enrichment/config"
+	"github.com/jackvonhouse/car-enrichment/internal/errors"
+	"github.com/jackvonhouse/car-enrichment/internal/identity"
+	"github.com/jackvonhouse/car-enrichment/pkg/log"
+	"time"
+)
+
+type quotaRepository interface {
+	Consume(context.Context, string, time.Time, int, int) (int, error)
+}
+
+type Service struct {
+	quota quotaRepository
+
+	config config.Quota
+
+	logger log.Logger
+}
+
+func New(
+	quota quotaRepository,
+	config config.Quota,
+	logger log.Logger,
+) Service {
+
+	return Service{
+		quota:  quota,
+		config: config,
+		logger: logger.WithField("unit", "quota"),
+	}
+}
+
+// ConsumeEnrichment списывает номера, отправляемые на обогащение,
+// из суточной квоты клиента из контекста. Квота ведётся по тому же
+// ключу клиента, что и лимит запросов, а не по имени из X-Actor
+func (s Service) ConsumeEnrichment(
+	ctx context.Context,
+	plates int,
+) error {
+
+	limit := s.config.DailyEnrichment
+	if limit <= 0 || plates <= 0 {
+		return nil
+	}
+
+	if plates > limit {
+		return errors.ErrLimitExceeded.New("daily enrichment quota exceeded")
+	}
+
+	client := identity.FromContext(ctx).Client
+	if client == "" {
+		client = identity.Anonymous
+	}
+
+	used, err := s.quota.Consume(ctx, client, time.Now().UTC(), plates, limit)
+	if err != nil {
+		return err
+	}
+
+	s.logger.WithContext(ctx).Debugf("client %s used %d of %d enrichments today", client, used, limit)
+
+	return nil
+}
@@ -0,0 +1,201 @@
+package token
+
+import (
+	"context"
+	"crypto/rsa"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/jackvonhouse/car-enrichment/config"
+	"github.com/jackvonhouse/car-enrichment/internal/errors"
+	"github.com/jackvonhouse/car-enrichment/internal/identity"
+	errpkg "github.com/jackvonhouse/car-enrichment/pkg/errors"
+	"github.com/jackvonhouse/car-enrichment/pkg/log"
+	"os"
+	"slices"
+)
+
+type Service struct {
+	config config.JWT
+
+	secret []byte
+	// keys — открытые ключи RS256 по kid, ключ из PEM хранится под пустым kid
+	keys   map[string]*rsa.PublicKey
+	parser *jwt.Parser
+
+	logger log.Logger
+}
+
+// New загружает ключи проверки токенов. Ошибка в ключах
+// останавливает запуск, а не проявляется на первом запросе
+func New(
+	config config.JWT,
+	logger log.Logger,
+) (Service, error) {
+
+	s := Service{
+		config: config,
+		keys:   make(map[string]*rsa.PublicKey),
+		logger: logger.WithField("unit", "token"),
+	}
+
+	if !config.Enabled {
+		return s, nil
+	}
+
+	methods := make([]string, 0, 2)
+
+	if config.Secret != "" {
+		s.secret = []byte(config.Secret)
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+
+	if config.PublicKeyFile != "" {
+		data, err := os.ReadFile(config.PublicKeyFile)
+		if err != nil {
+			return Service{}, errors.ErrInvalid.New("can't read jwt public key").Wrap(err)
+		}
+
+		key, err := jwt.ParseRSAPublicKeyFromPEM(data)
+		if err != nil {
+			return Service{}, errors.ErrInvalid.New("invalid jwt public key").Wrap(err)
+		}
+
+		s.keys[""] = key
+	}
+
+	if config.JWKSFile != "" {
+		keys, err := loadJWKS(config.JWKSFile)
+		if err != nil {
+			return Service{}, errors.ErrInvalid.New("can't load jwks").Wrap(err)
+		}
+
+		for kid, key := range keys {
+			s.keys[kid] = key
+		}
+	}
+
+	if len(s.keys) != 0 {
+		methods = append(methods, jwt.SigningMethodRS256.Alg())
+	}
+
+	if len(methods) == 0 {
+		return Service{}, errors.ErrInvalid.New("jwt is enabled, but neither secret nor public keys are set")
+	}
+
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods(methods),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(config.Leeway),
+	}
+
+	if config.Issuer != "" {
+		options = append(options, jwt.WithIssuer(config.Issuer))
+	}
+
+	if config.Audience != "" {
+		options = append(options, jwt.WithAudience(config.Audience))
+	}
+
+	s.parser = jwt.NewParser(options...)
+
+	return s, nil
+}
+
+// Authenticate проверяет токен и возвращает пользователя
+// с правами его ролей. Неизвестные роли не дают прав
+func (s Service) Authenticate(
+	ctx context.Context,
+	raw string,
+) (identity.Identity, error) {
+
+	if !s.config.Enabled {
+		return identity.Identity{}, errors.ErrUnauthorized.New("bearer tokens are not accepted")
+	}
+
+	claims := jwt.MapClaims{}
+
+	if _, err := s.parser.ParseWithClaims(raw, claims, s.key); err != nil {
+		s.logger.WithContext(ctx).Infof("invalid token: %s", err)
+
+		if errpkg.Is(err, jwt.ErrTokenExpired) {
+			return identity.Identity{}, errors.ErrUnauthorized.New("token expired").Wrap(err)
+		}
+
+		return identity.Identity{}, errors.ErrUnauthorized.New("invalid token").Wrap(err)
+	}
+
+	subject, err := claims.GetSubject()
+	if err != nil || subject == "" {
+		return identity.Identity{}, errors.ErrUnauthorized.New("token without subject")
+	}
+
+	scopes := make([]identity.Scope, 0)
+
+	for _, role := range s.roles(claims) {
+		for _, scope := range identity.RoleScopes[role] {
+			if !slices.Contains(scopes, scope) {
+				scopes = append(scopes, scope)
+			}
+		}
+	}
+
+	return identity.Identity{
+		Name:   subject,
+		Scopes: scopes,
+	}, nil
+}
+
+func (s Service) key(
+	token *jwt.Token,
+) (any, error) {
+
+	switch token.Method.(type) {
+
+	case *jwt.SigningMethodHMAC:
+		return s.secret, nil
+
+	case *jwt.SigningMethodRSA:
+		kid, _ := token.Header["kid"].(string)
+
+		if key, ok := s.keys[kid]; ok {
+			return key, nil
+		}
+
+		// Без kid подходит только единственный ключ
+		if kid == "" && len(s.keys) == 1 {
+			for _, key := range s.keys {
+				return key, nil
+			}
+		}
+
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+
+	return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+}
+
+// roles читает роль из claim, заданного в конфигурации:
+// строку или массив строк
+func (s Service) roles(
+	claims jwt.MapClaims,
+) []identity.Role {
+
+	switch value := claims[s.config.RoleClaim].(type) {
+
+	case string:
+		return []identity.Role{identity.Role(value)}
+
+	case []any:
+		roles := make([]identity.Role, 0, len(value))
+
+		for _, item := range value {
+			if role, ok := item.(string); ok {
+				roles = append(roles, identity.Role(role))
+			}
+		}
+
+		return roles
+	}
+
+	return nil
+}
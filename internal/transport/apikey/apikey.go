@@ -0,0 +1,200 @@
+package apikey
+
+import (
+	"context"
+	"github.com/gorilla/mux"
+	"github.com/jackvonhouse/car-enrichment/internal/dto"
+	"github.com/jackvonhouse/car-enrichment/internal/identity"
+	"github.com/jackvonhouse/car-enrichment/internal/transport"
+	"github.com/jackvonhouse/car-enrichment/internal/transport/router"
+	"github.com/jackvonhouse/car-enrichment/pkg/log"
+	"github.com/jackvonhouse/car-enrichment/pkg/validate"
+	"net/http"
+	"time"
+)
+
+type apiKeyUseCase interface {
+	Issue(context.Context, dto.CreateAPIKey) (dto.IssuedAPIKey, error)
+
+	Get(context.Context) ([]dto.APIKey, error)
+
+	Revoke(context.Context, int64) error
+}
+
+const maxNameLength = 100
+
+type Transport struct {
+	apiKey apiKeyUseCase
+
+	logger log.Logger
+}
+
+func New(
+	apiKey apiKeyUseCase,
+	logger log.Logger,
+) Transport {
+	return Transport{
+		apiKey: apiKey,
+		logger: logger.WithField("unit", "api key"),
+	}
+}
+
+func (t Transport) Handle(
+	r *mux.Router,
+) {
+	r.Use(router.RequireScope(identity.ScopeAdmin))
+
+	r.HandleFunc("", t.Issue).
+		Methods(http.MethodPost)
+
+	r.HandleFunc("", t.Get).
+		Methods(http.MethodGet)
+
+	r.HandleFunc("/{id:[0-9]+}", t.Revoke).
+		Methods(http.MethodDelete)
+}
+
+func (t Transport) error(
+	w http.ResponseWriter,
+	r *http.Request,
+	err error,
+) {
+
+	t.logger.WithContext(r.Context()).Warn(err)
+
+	transport.ErrorFrom(w, err, transport.DefaultErrorHttpCodes)
+}
+
+// Issue godoc
+// @Summary			Выдать ключ API
+// @Description		Выдача ключа с правами: read — чтение, write — создание и изменение,
+// @Description		delete — удаление, admin — все права и управление ключами.
+// @Description		Ключ возвращается только в этом ответе, сохраните его.
+// @Description		Без expiresAt ключ бессрочный
+// @Accept			json
+// @Produce			json
+// @Param			request body dto.CreateAPIKey true "Ключ"
+// @Success			200 {object} dto.IssuedAPIKey
+// @Failure			400 {object} transport.Problem "Некорректный JSON"
+// @Failure			422 {object} transport.Problem "Некорректное имя, права или срок"
+// @Failure			401 {object} transport.Problem "Нет ключа или ключ недействителен"
+// @Failure			403 {object} transport.Problem "Нет права admin"
+// @Failure			409 {object} transport.Problem "Ключ с таким именем уже существует"
+// @Failure			500 {object} transport.Problem "Неизвестная ошибка"
+// @Security		ApiKeyAuth
+// @Security		BearerAuth
+// @Tags			Ключи API
+// @Router /apikey [post]
+func (t Transport) Issue(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+
+	data := dto.CreateAPIKey{}
+
+	if err := transport.DecodeJSON(r, &data); err != nil {
+		transport.ErrorFrom(w, err, transport.DefaultErrorHttpCodes)
+
+		return
+	}
+
+	v := validate.New()
+
+	validate.Field(v, "name", data.Name, validate.Required(), validate.Length(0, maxNameLength))
+	validate.Field(v, "scopes", data.Scopes, validate.NotEmpty[identity.Scope]())
+	validate.Each(v, "scopes", data.Scopes, validate.OneOf(identity.Scopes...))
+
+	if data.ExpiresAt != nil {
+		v.Check("expiresAt", data.ExpiresAt.After(time.Now()), "must be in the future")
+	}
+
+	if err := v.Err(); err != nil {
+		transport.ErrorFrom(w, err, transport.DefaultErrorHttpCodes)
+
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	issued, err := t.apiKey.Issue(ctx, data)
+	if err != nil {
+		t.error(w, r, err)
+
+		return
+	}
+
+	transport.Response(w, issued)
+}
+
+// Get godoc
+// @Summary			Получить ключи API
+// @Description		Получение всех ключей, включая отозванные и истёкшие.
+// @Description		Вместо ключа показывается его начало
+// @Accept			json
+// @Produce			json
+// @Success			200 {array} dto.APIKey
+// @Failure			401 {object} transport.Problem "Нет ключа или ключ недействителен"
+// @Failure			403 {object} transport.Problem "Нет права admin"
+// @Failure			500 {object} transport.Problem "Неизвестная ошибка"
+// @Security		ApiKeyAuth
+// @Security		BearerAuth
+// @Tags			Ключи API
+// @Router /apikey [get]
+func (t Transport) Get(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	keys, err := t.apiKey.Get(ctx)
+	if err != nil {
+		t.error(w, r, err)
+
+		return
+	}
+
+	transport.Response(w, keys)
+}
+
+// Revoke godoc
+// @Summary			Отозвать ключ API
+// @Description		Отзыв ключа. Отозванный ключ остаётся в списке и не принимается
+// @Accept			json
+// @Produce			json
+// @Param			id path int true "Идентификатор ключа"
+// @Success			200 {object} object{result=bool}
+// @Failure			400 {object} transport.Problem "Некорректный идентификатор"
+// @Failure			401 {object} transport.Problem "Нет ключа или ключ недействителен"
+// @Failure			403 {object} transport.Problem "Нет права admin"
+// @Failure			404 {object} transport.Problem "Ключ не найден или уже отозван"
+// @Failure			500 {object} transport.Problem "Неизвестная ошибка"
+// @Security		ApiKeyAuth
+// @Security		BearerAuth
+// @Tags			Ключи API
+// @Router /apikey/{id} [delete]
+func (t Transport) Revoke(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+
+	keyId, err := transport.StringToInt(mux.Vars(r)["id"])
+	if err != nil || keyId <= 0 {
+		transport.Error(w, http.StatusBadRequest, "invalid api key id")
+
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	if err := t.apiKey.Revoke(ctx, int64(keyId)); err != nil {
+		t.error(w, r, err)
+
+		return
+	}
+
+	transport.Response(w, map[string]any{"success": true})
+}
@@ -0,0 +1,144 @@
+package audit
+
+import (
+	"context"
+	"github.com/gorilla/mux"
+	"github.com/jackvonhouse/car-enrichment/internal/dto"
+	"github.com/jackvonhouse/car-enrichment/internal/identity"
+	"github.com/jackvonhouse/car-enrichment/internal/transport"
+	"github.com/jackvonhouse/car-enrichment/internal/transport/router"
+	"github.com/jackvonhouse/car-enrichment/pkg/log"
+	"github.com/jackvonhouse/car-enrichment/pkg/validate"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+type auditUseCase interface {
+	Get(context.Context, dto.AuditFilter, dto.Pagination) ([]dto.Audit, error)
+}
+
+type Transport struct {
+	audit auditUseCase
+
+	logger log.Logger
+}
+
+func New(
+	audit auditUseCase,
+	logger log.Logger,
+) Transport {
+	return Transport{
+		audit:  audit,
+		logger: logger.WithField("unit", "audit"),
+	}
+}
+
+func (t Transport) Handle(
+	r *mux.Router,
+) {
+	r.Handle("", router.Scoped(identity.ScopeRead, t.Get)).
+		Methods(http.MethodGet)
+}
+
+// Get godoc
+// @Summary			Получить журнал аудита
+// @Description		Получение журнала изменений автомобилей, владельцев и ключей API.
+// @Description		Действия: create, update, delete, revoke и transfer — передача
+// @Description		автомобиля другому владельцу
+// @Accept			json
+// @Produce			json
+// @Param			limit query int false "Лимит, от 1 до 100" default(10) minimum(1) maximum(100)
+// @Param			offset query int false "Смещение"
+// @Param			entity query string false "Сущность" Enums(car, owner, api_key)
+// @Param			entityId query int false "Идентификатор сущности"
+// @Param			actor query string false "Автор изменения"
+// @Param			from query string false "Начало периода (RFC 3339)"
+// @Param			to query string false "Конец периода (RFC 3339)"
+// @Success			200 {array} dto.Audit
+// @Failure			422 {object} transport.Problem "Некорректный фильтр или пагинация"
+// @Failure			500 {object} transport.Problem "Неизвестная ошибка"
+// @Security		ApiKeyAuth
+// @Security		BearerAuth
+// @Tags			Аудит
+// @Router /audit [get]
+func (t Transport) Get(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+
+	queries := r.URL.Query()
+
+	v := validate.New()
+
+	filter := dto.AuditFilter{
+		Entity: queries.Get("entity"),
+		Actor:  queries.Get("actor"),
+	}
+
+	if filter.Entity != "" {
+		validate.Field(v, "entity", filter.Entity, validate.OneOf(
+			dto.AuditEntityCar, dto.AuditEntityOwner, dto.AuditEntityAPIKey,
+		))
+	}
+
+	if entityId := queries.Get("entityId"); entityId != "" {
+		id, err := transport.StringToInt(entityId)
+		if err != nil {
+			v.Add("entityId", "must be an integer")
+		} else {
+			validate.Field(v, "entityId", id, validate.Min(1))
+		}
+
+		filter.EntityID = int64(id)
+	}
+
+	filter.From = parseTime(queries, "from", v)
+	filter.To = parseTime(queries, "to", v)
+
+	limit, offset := transport.ParsePagination(queries, v)
+
+	if err := v.Err(); err != nil {
+		transport.ErrorFrom(w, err, transport.DefaultErrorHttpCodes)
+
+		return
+	}
+
+	pagination := dto.Pagination{
+		Limit:  limit,
+		Offset: offset,
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	records, err := t.audit.Get(ctx, filter, pagination)
+	if err != nil {
+		t.logger.WithContext(r.Context()).Warn(err)
+
+		transport.ErrorFrom(w, err, transport.DefaultErrorHttpCodes)
+
+		return
+	}
+
+	transport.Response(w, records)
+}
+
+func parseTime(
+	queries url.Values,
+	name string,
+	v *validate.Validator,
+) time.Time {
+
+	value := queries.Get(name)
+	if value == "" {
+		return time.Time{}
+	}
+
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		v.Add(name, "must be a RFC 3339 date")
+	}
+
+	return parsed
+}
@@ -2,12 +2,20 @@ package car
 
 import (
 	"context"
-	"encoding/json"
+	"fmt"
 	"github.com/gorilla/mux"
 	"github.com/jackvonhouse/car-enrichment/internal/dto"
+	"github.com/jackvonhouse/car-enrichment/internal/errors"
+	"github.com/jackvonhouse/car-enrichment/internal/identity"
 	"github.com/jackvonhouse/car-enrichment/internal/transport"
+	"github.com/jackvonhouse/car-enrichment/internal/transport/router"
+	errpkg "github.com/jackvonhouse/car-enrichment/pkg/errors"
 	"github.com/jackvonhouse/car-enrichment/pkg/log"
+	"github.com/jackvonhouse/car-enrichment/pkg/plate"
+	"github.com/jackvonhouse/car-enrichment/pkg/validate"
 	"net/http"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -15,7 +23,11 @@ import (
 type carUseCase interface {
 	Create(context.Context, dto.CreateCar) (map[int64]string, error)
 
-	Get(context.Context, dto.Filter, dto.Pagination) ([]dto.Car, error)
+	Get(context.Context, dto.Filter, dto.Pagination) (dto.CarList, error)
+
+	Iterate(context.Context, dto.Filter, []dto.Sort, func(dto.Car) error) error
+
+	Import(context.Context, []dto.ImportRow, dto.ImportOptions) (dto.ImportResult, error)
 
 	Update(context.Context, dto.Car) error
 
@@ -38,44 +50,49 @@ func New(
 	}
 }
 
-func (t Transport) loggerMiddleware(
-	next http.Handler,
-) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		t.logger.Infof("[%s] received request %s", r.Method, r.URL.String())
-
-		next.ServeHTTP(w, r)
-	})
-}
-
+// Handle объявляет маршруты вместе с правами: чтение доступно роли viewer,
+// создание и изменение — editor, удаление — только admin
 func (t Transport) Handle(
-	router *mux.Router,
+	r *mux.Router,
 ) {
-	logRouter := router.PathPrefix("").Subrouter()
-	logRouter.Use(t.loggerMiddleware)
-
-	logRouter.HandleFunc("", t.Create).
+	r.Handle("", router.Scoped(identity.ScopeWrite, t.Create)).
 		Methods(http.MethodPost)
 
-	logRouter.HandleFunc("", t.Get).
+	r.Handle("", router.Scoped(identity.ScopeRead, t.Get)).
+		Methods(http.MethodGet)
+
+	r.Handle("/export", router.Scoped(identity.ScopeRead, t.Export)).
 		Methods(http.MethodGet)
 
-	logRouter.HandleFunc("/{id:[0-9]+}", t.Update).
+	r.Handle("/import", router.Scoped(identity.ScopeWrite, t.Import)).
+		Methods(http.MethodPost)
+
+	r.Handle("/{id:[0-9]+}", router.Scoped(identity.ScopeWrite, t.Update)).
 		Methods(http.MethodPut)
 
-	logRouter.HandleFunc("/{id:[0-9]+}", t.Delete).
+	r.Handle("/{id:[0-9]+}", router.Scoped(identity.ScopeDelete, t.Delete)).
 		Methods(http.MethodDelete)
 }
 
 // Create godoc
 // @Summary			Создание автомобиля
-// @Description		Создание и обогощение автомобиля
+// @Description		Создание и обогощение автомобиля.
+// @Description		Номера проверяются по ГОСТ Р 50577 и сохраняются в каноническом виде:
+// @Description		без пробелов, в верхнем регистре, похожие латинские буквы заменяются кириллицей.
+// @Description		Каждый номер списывается из суточной квоты обогащения клиента
 // @Accept			json
 // @Produce			json
 // @Param			request body dto.CreateCar true "Массив гос. номеров"
 // @Success			200 {object} object{result=bool}
-// @Failure			409 {object} object{error=string} "Автомобиль или владелец уже существует"
-// @Failure			500 {object} object{error=string} "Неизвестная ошибка"
+// @Failure			400 {object} transport.Problem "Некорректный JSON или неизвестное поле"
+// @Failure			422 {object} transport.Problem "Пустой список или некорректные гос. номера"
+// @Failure			409 {object} transport.Problem "Автомобиль или владелец уже существует"
+// @Failure			429 {object} transport.Problem "Превышен лимит запросов или суточная квота обогащения"
+// @Failure			401 {object} transport.Problem "Нет ключа или токена, либо они недействительны"
+// @Failure			403 {object} transport.Problem "Недостаточно прав"
+// @Failure			500 {object} transport.Problem "Неизвестная ошибка"
+// @Security		ApiKeyAuth
+// @Security		BearerAuth
 // @Tags			Автомобиль
 // @Router /car [post]
 func (t Transport) Create(
@@ -85,26 +102,35 @@ func (t Transport) Create(
 
 	data := dto.CreateCar{}
 
-	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
-		transport.Error(w, http.StatusInternalServerError, "invalid json structure")
+	if err := transport.DecodeJSON(r, &data); err != nil {
+		transport.ErrorFrom(w, err, transport.DefaultErrorHttpCodes)
 
 		return
 	}
 
-	if len(data.RegNumbers) == 0 {
-		transport.Error(w, http.StatusBadRequest, "empty registration numbers")
+	if err := validateCreate(data); err != nil {
+		transport.ErrorFrom(w, err, transport.DefaultErrorHttpCodes)
 
 		return
 	}
 
+	regNumbers := make([]string, 0, len(data.RegNumbers))
+	seen := make(map[string]struct{}, len(data.RegNumbers))
+
 	for _, regNumber := range data.RegNumbers {
-		if len(strings.TrimSpace(regNumber)) == 0 {
-			transport.Error(w, http.StatusBadRequest, "empty registration number")
+		p, _ := plate.Parse(regNumber)
 
-			return
+		// Разные записи одного номера приводятся к одной
+		if _, ok := seen[p.Number]; ok {
+			continue
 		}
+
+		seen[p.Number] = struct{}{}
+		regNumbers = append(regNumbers, p.Number)
 	}
 
+	data.RegNumbers = regNumbers
+
 	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
 	defer cancel()
 
@@ -119,14 +145,13 @@ func (t Transport) Create(
 	}
 
 	if err != nil {
-		t.logger.Warn(err)
+		t.logger.WithContext(r.Context()).Warn(err)
 
-		code, msg := transport.ErrorToHttpResponse(
-			err,
-			transport.DefaultErrorHttpCodes,
-		)
+		if errpkg.TypeIs(err, errors.ErrLimitExceeded) {
+			quotaRetryAfter(w)
+		}
 
-		transport.Error(w, code, msg)
+		transport.ErrorFrom(w, err, transport.DefaultErrorHttpCodes)
 
 		return
 	}
@@ -136,21 +161,50 @@ func (t Transport) Create(
 
 // Get godoc
 // @Summary			Получить автомобилей
-// @Description		Получение автомобилей с возможностью фильтрации
+// @Description		Получение автомобилей с возможностью фильтрации.
+// @Description		Ответ оборачивается в страницу с общим количеством и ссылками.
+// @Description		Для прежнего формата (массив) передайте "Accept: application/json; version=1".
+// @Description		С "Accept: application/x-ndjson" все подходящие автомобили отдаются потоком,
+// @Description		по одному объекту на строку, limit, offset и cursor при этом не учитываются.
+// @Description		Фильтры задаются как "поле=значение" или "поле[оператор]=значение".
+// @Description		Операторы текстовых полей: eq, ne, in, like (по умолчанию), prefix, similar.
+// @Description		like и prefix не учитывают регистр и диакритику ("ё" равно "е"),
+// @Description		similar ищет нечётко по триграммному сходству, например "ownerSurname[similar]=Иванов".
+// @Description		Операторы года: eq (по умолчанию), ne, in, gte, lte, between.
+// @Description		Значения in и between перечисляются через запятую: "year[between]=2010,2015"
 // @Accept			json
 // @Produce			json
-// @Param			limit query int false "Лимит"
+// @Produce			application/x-ndjson
+// @Param			Accept header string false "Версия формата ответа" default(application/json; version=2)
+// @Param			limit query int false "Лимит, от 1 до 100" default(10) minimum(1) maximum(100)
 // @Param			offset query int false "Смещение"
+// @Param			cursor query string false "Курсор следующей страницы (nextCursor), несовместим с offset"
+// @Param			sort query string false "Сортировка через запятую, '-' — по убыванию: id, regNum, mark, model, year, ownerName, ownerSurname, ownerPatronymic" example(year,-mark)
 // @Param			regNum query string false "Гос. номер"
 // @Param			mark query string false "Марка"
 // @Param			model query string false "Модель"
 // @Param			year query int false "Год"
+// @Param			region query string false "Код региона, точное совпадение, операторы eq, ne, in"
+// @Param			vin query string false "VIN"
+// @Param			color query string false "Цвет"
+// @Param			bodyType query string false "Тип кузова, операторы eq, ne, in" Enums(sedan, hatchback, liftback, wagon, suv, coupe, convertible, minivan, pickup, van)
+// @Param			fuelType query string false "Тип топлива, операторы eq, ne, in" Enums(petrol, diesel, gas, hybrid, electric)
+// @Param			engineVolume query int false "Объём двигателя, см³"
+// @Param			power query int false "Мощность, л. с."
+// @Param			mileage query int false "Пробег, км"
+// @Param			mileageAt query string false "Дата пробега (2006-01-02), операторы eq, gte, lte, between"
 // @Param			ownerName query string false "Имя владельца"
 // @Param			ownerSurname query string false "Фамилия владельца"
 // @Param			ownerPatronymic query string false "Отчество владельца"
-// @Success			200 {array} dto.Car
-// @Failure			404 {object} object{error=string} "Автомобили отсутствуют"
-// @Failure			500 {object} object{error=string} "Неизвестная ошибка"
+// @Success			200 {object} transport.Page[dto.Car]
+// @Failure			400 {object} transport.Problem "Некорректный курсор"
+// @Failure			422 {object} transport.Problem "Некорректный фильтр, сортировка или пагинация"
+// @Failure			404 {object} transport.Problem "Автомобили отсутствуют"
+// @Failure			401 {object} transport.Problem "Нет ключа или токена, либо они недействительны"
+// @Failure			403 {object} transport.Problem "Недостаточно прав"
+// @Failure			500 {object} transport.Problem "Неизвестная ошибка"
+// @Security		ApiKeyAuth
+// @Security		BearerAuth
 // @Tags			Автомобиль
 // @Router /car [get]
 func (t Transport) Get(
@@ -160,51 +214,38 @@ func (t Transport) Get(
 
 	queries := r.URL.Query()
 
-	regNum := queries.Get("regNum")
-	mark := queries.Get("mark")
-	model := queries.Get("model")
-	ownerName := queries.Get("ownerName")
-	ownerSurname := queries.Get("ownerSurname")
-	ownerPatronymic := queries.Get("ownerPatronymic")
+	v := validate.New()
 
-	year, err := transport.StringToInt(queries.Get("year"))
-	if err != nil || year < 0 {
-		year = 0
-	}
+	filter := ParseFilter(queries, v)
+	sort := parseSort(queries.Get("sort"), v)
+	limit, offset := transport.ParsePagination(queries, v)
 
-	filter := dto.Filter{
-		RegNum:          regNum,
-		Mark:            mark,
-		Model:           model,
-		Year:            year,
-		OwnerName:       ownerName,
-		OwnerSurname:    ownerSurname,
-		OwnerPatronymic: ownerPatronymic,
+	cursor := queries.Get("cursor")
+	if !v.Has("offset") {
+		v.Check("cursor", cursor == "" || offset == 0, "is mutually exclusive with offset")
 	}
 
-	limit, err := transport.StringToInt(queries.Get("limit"))
-	if err != nil || limit <= 0 {
-		// В зависимости от логики выбрасывать ошибку
-		// или устанавливать limit по умолчанию
-		// transport.Error(w, http.StatusBadRequest, "invalid limit")
-		// return
+	if err := v.Err(); err != nil {
+		transport.ErrorFrom(w, err, transport.DefaultErrorHttpCodes)
 
-		limit = 10
+		return
 	}
 
-	offset, err := transport.StringToInt(queries.Get("offset"))
-	if err != nil || offset < 0 {
-		// В зависимости от логики выбрасывать ошибку
-		// или устанавливать offset по умолчанию
-		// transport.Error(w, http.StatusBadRequest, "invalid offset")
-		// return
+	if transport.Accepts(r, ndjsonContentType) {
+		t.stream(w, r, filter, sort)
 
-		offset = 0
+		return
 	}
 
+	// Прежний формат ответа без общего количества
+	legacy := transport.AcceptParam(r, "version") == "1"
+
 	pagination := dto.Pagination{
-		Limit:  limit,
-		Offset: offset,
+		Limit:     limit,
+		Offset:    offset,
+		Cursor:    cursor,
+		Sort:      sort,
+		WithTotal: !legacy,
 	}
 
 	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
@@ -212,32 +253,100 @@ func (t Transport) Get(
 
 	cars, err := t.car.Get(ctx, filter, pagination)
 	if err != nil {
-		t.logger.Warn(err)
+		t.logger.WithContext(r.Context()).Warn(err)
+
+		transport.ErrorFrom(w, err, transport.DefaultErrorHttpCodes)
+
+		return
+	}
 
-		code, msg := transport.ErrorToHttpResponse(
-			err,
-			transport.DefaultErrorHttpCodes,
-		)
+	if legacy {
+		transport.Response(w, cars.Items)
 
-		transport.Error(w, code, msg)
+		return
+	}
+
+	if cursor != "" {
+		transport.Response(w, transport.NewCursorPage(r, cars.Items, cars.Total, limit, cars.NextCursor))
 
 		return
 	}
 
-	transport.Response(w, cars)
+	transport.Response(w, transport.NewPage(r, cars.Items, cars.Total, limit, offset).
+		WithNextCursor(cars.NextCursor))
+}
+
+// sortFields — поля, по которым можно сортировать
+var sortFields = []string{
+	"id", "regNum", "mark", "model", "year", "ownerName", "ownerSurname", "ownerPatronymic",
+}
+
+// parseSort разбирает сортировку вида "year,-mark",
+// ошибки добавляются в v
+func parseSort(
+	value string,
+	v *validate.Validator,
+) []dto.Sort {
+
+	if strings.TrimSpace(value) == "" {
+		return nil
+	}
+
+	fields := strings.Split(value, ",")
+	sort := make([]dto.Sort, 0, len(fields))
+	seen := make(map[string]struct{}, len(fields))
+
+	for _, field := range fields {
+		field = strings.TrimSpace(field)
+
+		desc := strings.HasPrefix(field, "-")
+		field = strings.TrimPrefix(field, "-")
+
+		if !slices.Contains(sortFields, field) {
+			v.Add("sort", fmt.Sprintf("unknown field %q", field))
+
+			continue
+		}
+
+		if _, ok := seen[field]; ok {
+			v.Add("sort", fmt.Sprintf("duplicate field %q", field))
+
+			continue
+		}
+
+		seen[field] = struct{}{}
+
+		sort = append(sort, dto.Sort{
+			Field: field,
+			Desc:  desc,
+		})
+	}
+
+	return sort
 }
 
 // Update godoc
 // @Summary			Обновить автомобиль
-// @Description		Обновление автомобиля
+// @Description		Обновление автомобиля.
+// @Description		VIN проверяется по ISO 3779, контрольная цифра обязательна для VIN Северной Америки.
+// @Description		Марка и модель приводятся к названиям из справочника, markKnown и modelKnown
+// @Description		показывают, найдены ли они в нём.
+// @Description		Пробег без даты считается полученным в момент запроса.
+// @Description		Другой owner.id передаёт автомобиль этому владельцу, имя владельца
+// @Description		при этом не меняется. Без owner.id или с прежним поля владельца
+// @Description		изменяют текущего владельца
 // @Accept			json
 // @Produce			json
 // @Param			request body dto.Car true "Данные об автомобиле"
 // @Param			id path int true "Идентификатор автомобиля"
 // @Success			200 {object} object{result=bool}
-// @Failure			404 {object} object{error=string} "Автомобиль или владелец не найдены"
-// @Failure			409 {object} object{error=string} "Автомобиль уже существует"
-// @Failure			500 {object} object{error=string} "Неизвестная ошибка"
+// @Failure			404 {object} transport.Problem "Автомобиль или владелец не найдены"
+// @Failure			409 {object} transport.Problem "Автомобиль уже существует"
+// @Failure			401 {object} transport.Problem "Нет ключа или токена, либо они недействительны"
+// @Failure			403 {object} transport.Problem "Недостаточно прав"
+// @Failure			500 {object} transport.Problem "Неизвестная ошибка"
+// @Security		ApiKeyAuth
+// @Security		BearerAuth
 // @Tags			Автомобиль
 // @Router /car/{id} [put]
 func (t Transport) Update(
@@ -256,32 +365,28 @@ func (t Transport) Update(
 
 	data := dto.Car{}
 
-	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
-		transport.Error(w, http.StatusInternalServerError, "invalid json structure")
+	if err := transport.DecodeJSON(r, &data); err != nil {
+		transport.ErrorFrom(w, err, transport.DefaultErrorHttpCodes)
 
 		return
 	}
 
-	data.ID = int64(carId)
-
-	if data.Year != 0 && (data.Year < 1900 || data.Year > time.Now().Year()) {
-		transport.Error(w, http.StatusBadRequest, "invalid year")
+	if err := validateCar(data); err != nil {
+		transport.ErrorFrom(w, err, transport.DefaultErrorHttpCodes)
 
 		return
 	}
 
+	data.ID = int64(carId)
+	normalizeCar(&data)
+
 	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
 	defer cancel()
 
 	if err := t.car.Update(ctx, data); err != nil {
-		t.logger.Warn(err)
-
-		code, msg := transport.ErrorToHttpResponse(
-			err,
-			transport.DefaultErrorHttpCodes,
-		)
+		t.logger.WithContext(r.Context()).Warn(err)
 
-		transport.Error(w, code, msg)
+		transport.ErrorFrom(w, err, transport.DefaultErrorHttpCodes)
 
 		return
 	}
@@ -296,8 +401,12 @@ func (t Transport) Update(
 // @Produce			json
 // @Param			id path int true "Идентификатор автомобиля"
 // @Success			200 {object} object{result=bool}
-// @Failure			404 {object} object{error=string} "Автомобиль не найден"
-// @Failure			500 {object} object{error=string} "Неизвестная ошибка"
+// @Failure			404 {object} transport.Problem "Автомобиль не найден"
+// @Failure			401 {object} transport.Problem "Нет ключа или токена, либо они недействительны"
+// @Failure			403 {object} transport.Problem "Недостаточно прав"
+// @Failure			500 {object} transport.Problem "Неизвестная ошибка"
+// @Security		ApiKeyAuth
+// @Security		BearerAuth
 // @Tags			Автомобиль
 // @Router /car/{id} [delete]
 func (t Transport) Delete(
@@ -318,17 +427,22 @@ func (t Transport) Delete(
 	defer cancel()
 
 	if err := t.car.Delete(ctx, int64(carId)); err != nil {
-		t.logger.Warn(err)
+		t.logger.WithContext(r.Context()).Warn(err)
 
-		code, msg := transport.ErrorToHttpResponse(
-			err,
-			transport.DefaultErrorHttpCodes,
-		)
-
-		transport.Error(w, code, msg)
+		transport.ErrorFrom(w, err, transport.DefaultErrorHttpCodes)
 
 		return
 	}
 
 	transport.Response(w, map[string]any{"success": true})
 }
+
+// quotaRetryAfter указывает, когда обнулится суточная квота обогащения
+func quotaRetryAfter(
+	w http.ResponseWriter,
+) {
+
+	retryAfter := time.Until(dto.QuotaReset(time.Now()))
+
+	w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter.Seconds())+1))
+}
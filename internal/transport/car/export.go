@@ -0,0 +1,356 @@
+package car
+
+import (
+	"encoding/csv"
+	"fmt"
+	"github.com/jackvonhouse/car-enrichment/internal/dto"
+	"github.com/jackvonhouse/car-enrichment/internal/transport"
+	"github.com/jackvonhouse/car-enrichment/pkg/validate"
+	"github.com/jackvonhouse/car-enrichment/pkg/xlsx"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type exportColumn struct {
+	name  string
+	value func(dto.Car) any
+}
+
+// exportColumns — столбцы выгрузки в порядке по умолчанию,
+// данные владельца разворачиваются в отдельные столбцы
+var exportColumns = []exportColumn{
+	{"id", func(c dto.Car) any { return c.ID }},
+	{"regNum", func(c dto.Car) any { return c.RegNum }},
+	{"mark", func(c dto.Car) any { return c.Mark }},
+	{"model", func(c dto.Car) any { return c.Model }},
+	{"year", func(c dto.Car) any { return optional(&c.Year, c.Year != 0) }},
+	{"region", func(c dto.Car) any {
+		if c.Region == nil {
+			return nil
+		}
+
+		return c.Region.Code
+	}},
+	{"regionSubject", func(c dto.Car) any {
+		if c.Region == nil || c.Region.Subject == "" {
+			return nil
+		}
+
+		return c.Region.Subject
+	}},
+	{"vin", func(c dto.Car) any { return optional(c.VIN, c.VIN != nil) }},
+	{"color", func(c dto.Car) any { return optional(c.Color, c.Color != nil) }},
+	{"bodyType", func(c dto.Car) any { return optional(c.BodyType, c.BodyType != nil) }},
+	{"fuelType", func(c dto.Car) any { return optional(c.FuelType, c.FuelType != nil) }},
+	{"engineVolume", func(c dto.Car) any { return optional(c.EngineVolume, c.EngineVolume != nil) }},
+	{"power", func(c dto.Car) any { return optional(c.Power, c.Power != nil) }},
+	{"mileage", func(c dto.Car) any { return optional(c.Mileage, c.Mileage != nil) }},
+	{"mileageAt", func(c dto.Car) any { return optional(c.MileageAt, c.MileageAt != nil) }},
+	{"ownerId", func(c dto.Car) any { return c.Owner.ID }},
+	{"ownerName", func(c dto.Car) any { return c.Owner.Name }},
+	{"ownerSurname", func(c dto.Car) any { return c.Owner.Surname }},
+	{"ownerPatronymic", func(c dto.Car) any { return c.Owner.Patronymic }},
+}
+
+// optional разыменовывает указатель на известное значение,
+// неизвестное значение выгружается пустой ячейкой
+func optional[T any](
+	value *T,
+	known bool,
+) any {
+
+	if !known {
+		return nil
+	}
+
+	switch v := any(*value).(type) {
+
+	case dto.BodyType:
+		return string(v)
+
+	case dto.FuelType:
+		return string(v)
+	}
+
+	return *value
+}
+
+func parseColumns(
+	value string,
+	v *validate.Validator,
+) []exportColumn {
+
+	if strings.TrimSpace(value) == "" {
+		return exportColumns
+	}
+
+	byName := make(map[string]exportColumn, len(exportColumns))
+	for _, column := range exportColumns {
+		byName[column.name] = column
+	}
+
+	names := strings.Split(value, ",")
+	columns := make([]exportColumn, 0, len(names))
+
+	for _, name := range names {
+		column, ok := byName[strings.TrimSpace(name)]
+		if !ok {
+			v.Add("columns", fmt.Sprintf("unknown column %q", strings.TrimSpace(name)))
+
+			continue
+		}
+
+		columns = append(columns, column)
+	}
+
+	return columns
+}
+
+type exporter interface {
+	Write([]any) error
+	Flush() error
+	Close() error
+}
+
+type csvExporter struct {
+	writer *csv.Writer
+}
+
+func (e csvExporter) Write(
+	cells []any,
+) error {
+
+	record := make([]string, len(cells))
+
+	for i, cell := range cells {
+		switch v := cell.(type) {
+
+		case nil:
+
+		case time.Time:
+			record[i] = v.Format(time.RFC3339)
+
+		case string:
+			record[i] = escapeFormula(v)
+
+		default:
+			record[i] = fmt.Sprint(v)
+		}
+	}
+
+	return e.writer.Write(record)
+}
+
+// formulaPrefixes — символы, с которых Excel начинает формулу
+const formulaPrefixes = "=+-@\t\r"
+
+// escapeFormula не даёт Excel выполнить значение ячейки как формулу:
+// такое значение предваряется апострофом, который Excel не показывает
+func escapeFormula(
+	value string,
+) string {
+
+	if value != "" && strings.ContainsRune(formulaPrefixes, rune(value[0])) {
+		return "'" + value
+	}
+
+	return value
+}
+
+// unescapeFormula снимает апостроф, добавленный escapeFormula,
+// чтобы выгруженный файл загружался обратно без изменений
+func unescapeFormula(
+	value string,
+) string {
+
+	if len(value) > 1 && value[0] == '\'' && strings.ContainsRune(formulaPrefixes, rune(value[1])) {
+		return value[1:]
+	}
+
+	return value
+}
+
+func (e csvExporter) Flush() error {
+	e.writer.Flush()
+
+	return e.writer.Error()
+}
+
+func (e csvExporter) Close() error { return e.Flush() }
+
+var exportFormats = map[string]struct {
+	contentType string
+	create      func(io.Writer) (exporter, error)
+}{
+	"csv": {
+		contentType: "text/csv; charset=utf-8",
+		create: func(w io.Writer) (exporter, error) {
+			// BOM нужен, чтобы Excel открыл кириллицу в UTF-8
+			if _, err := io.WriteString(w, "\ufeff"); err != nil {
+				return nil, err
+			}
+
+			return csvExporter{writer: csv.NewWriter(w)}, nil
+		},
+	},
+	"xlsx": {
+		contentType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+		create: func(w io.Writer) (exporter, error) {
+			return xlsx.NewWriter(w)
+		},
+	},
+}
+
+// Строк между сбросами буфера в ответ
+const exportFlushEvery = 500
+
+// Export godoc
+// @Summary			Выгрузить автомобили
+// @Description		Выгрузка всех автомобилей, подходящих под фильтры, в CSV или XLSX.
+// @Description		Фильтры и сортировка те же, что и при получении автомобилей, пагинации нет.
+// @Description		Строки читаются из базы и отправляются по мере чтения.
+// @Description		Столбцы: id, regNum, mark, model, year, region, regionSubject, vin, color, bodyType,
+// @Description		fuelType, engineVolume, power, mileage, mileageAt, ownerId, ownerName, ownerSurname, ownerPatronymic
+// @Produce			text/csv
+// @Produce			application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param			format query string false "Формат" Enums(csv, xlsx) default(csv)
+// @Param			columns query string false "Столбцы через запятую, по умолчанию все" example(regNum,mark,model,ownerSurname)
+// @Param			sort query string false "Сортировка через запятую, '-' — по убыванию: id, regNum, mark, model, year, ownerName, ownerSurname, ownerPatronymic"
+// @Param			regNum query string false "Гос. номер"
+// @Param			mark query string false "Марка"
+// @Param			model query string false "Модель"
+// @Param			year query int false "Год"
+// @Param			region query string false "Код региона, точное совпадение, операторы eq, ne, in"
+// @Success			200 {file} file
+// @Failure			422 {object} transport.Problem "Некорректный формат, столбец, фильтр или сортировка"
+// @Failure			401 {object} transport.Problem "Нет ключа или токена, либо они недействительны"
+// @Failure			403 {object} transport.Problem "Недостаточно прав"
+// @Failure			500 {object} transport.Problem "Неизвестная ошибка"
+// @Security		ApiKeyAuth
+// @Security		BearerAuth
+// @Tags			Автомобиль
+// @Router /car/export [get]
+func (t Transport) Export(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+
+	queries := r.URL.Query()
+
+	v := validate.New()
+
+	name := queries.Get("format")
+	if name == "" {
+		name = "csv"
+	}
+
+	format, ok := exportFormats[name]
+	v.Check("format", ok, "must be one of csv, xlsx")
+
+	columns := parseColumns(queries.Get("columns"), v)
+	filter := ParseFilter(queries, v)
+	sort := parseSort(queries.Get("sort"), v)
+
+	if err := v.Err(); err != nil {
+		transport.ErrorFrom(w, err, transport.DefaultErrorHttpCodes)
+
+		return
+	}
+
+	var (
+		ex      exporter
+		written int
+	)
+
+	// Ответ начинается с первой строки, чтобы ошибка до неё,
+	// например неверная сортировка, ещё могла вернуться обычным ответом
+	start := func() error {
+		w.Header().Set("Content-Type", format.contentType)
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="cars.%s"`, name))
+
+		created, err := format.create(w)
+		if err != nil {
+			return err
+		}
+
+		header := make([]any, len(columns))
+		for i, column := range columns {
+			header[i] = column.name
+		}
+
+		ex = created
+
+		return ex.Write(header)
+	}
+
+	err := t.car.Iterate(r.Context(), filter, sort, func(car dto.Car) error {
+		if ex == nil {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+
+		cells := make([]any, len(columns))
+		for i, column := range columns {
+			cells[i] = column.value(car)
+		}
+
+		if err := ex.Write(cells); err != nil {
+			return err
+		}
+
+		written++
+
+		if written%exportFlushEvery == 0 {
+			return t.flush(w, ex)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		t.logger.WithContext(r.Context()).Warn(err)
+
+		if ex != nil {
+			// Заголовки уже отправлены, остаётся оборвать выгрузку.
+			// Close не вызывается: закрытый файл выглядел бы полным
+			t.logger.WithContext(r.Context()).Warnf("export interrupted after %d rows", written)
+
+			panic(http.ErrAbortHandler)
+		}
+
+		transport.ErrorFrom(w, err, transport.DefaultErrorHttpCodes)
+
+		return
+	}
+
+	if ex == nil {
+		if err := start(); err != nil {
+			t.logger.WithContext(r.Context()).Warnf("can't start export: %s", err)
+
+			return
+		}
+	}
+
+	if err := ex.Close(); err != nil {
+		t.logger.WithContext(r.Context()).Warnf("can't finish export: %s", err)
+	}
+}
+
+func (t Transport) flush(
+	w http.ResponseWriter,
+	ex exporter,
+) error {
+
+	if err := ex.Flush(); err != nil {
+		return err
+	}
+
+	if flusher, ok := w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package car
+
+import (
+	"bytes"
+	"encoding/csv"
+	"github.com/jackvonhouse/car-enrichment/internal/errors"
+	"github.com/jackvonhouse/car-enrichment/pkg/log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCSVExporterEscapesFormulas(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"Иванов", "Иванов"},
+		{"=HYPERLINK(\"http://x\")", "'=HYPERLINK(\"http://x\")"},
+		{"+79990000000", "'+79990000000"},
+		{"-1", "'-1"},
+		{"@SUM(A1)", "'@SUM(A1)"},
+		{"\t=1", "'\t=1"},
+		{"'quoted", "'quoted"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		buf := &bytes.Buffer{}
+		ex := csvExporter{writer: csv.NewWriter(buf)}
+
+		if err := ex.Write([]any{tt.value, 42}); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := ex.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		record, err := csv.NewReader(buf).Read()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if record[0] != tt.want {
+			t.Errorf("escape %q: expected %q, got %q", tt.value, tt.want, record[0])
+		}
+
+		if record[1] != "42" {
+			t.Errorf("numbers must not be escaped, got %q", record[1])
+		}
+
+		if got := unescapeFormula(record[0]); got != tt.value {
+			t.Errorf("unescape %q: expected %q, got %q", record[0], tt.value, got)
+		}
+	}
+}
+
+func TestExportAbortsOnIterateError(t *testing.T) {
+	transport := New(iterateUseCase{rows: 3, err: errors.ErrInternal.New("connection reset")}, log.NewLogrusLogger())
+
+	r := httptest.NewRequest(http.MethodGet, "/car/export?format=csv", nil)
+	w := httptest.NewRecorder()
+
+	defer func() {
+		if value := recover(); value != http.ErrAbortHandler {
+			t.Fatalf("expected panic with http.ErrAbortHandler, got %v", value)
+		}
+	}()
+
+	transport.Export(w, r)
+}
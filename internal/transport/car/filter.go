@@ -0,0 +1,162 @@
+package car
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/jackvonhouse/car-enrichment/internal/dto"
+	"github.com/jackvonhouse/car-enrichment/internal/transport"
+	"github.com/jackvonhouse/car-enrichment/pkg/plate"
+	"github.com/jackvonhouse/car-enrichment/pkg/validate"
+	"github.com/jackvonhouse/car-enrichment/pkg/vin"
+)
+
+type filterField struct {
+	kind   transport.FieldKind
+	target func(*dto.Filter) *[]dto.Condition
+	// normalize приводит значения к виду, в котором они хранятся
+	normalize func(string) string
+	// valid проверяет значения полей FieldEnum
+	valid func(string) bool
+}
+
+var filterFields = map[string]filterField{
+	"regNum": {
+		kind:      transport.FieldText,
+		target:    func(f *dto.Filter) *[]dto.Condition { return &f.RegNum },
+		normalize: plate.Normalize,
+	},
+	"mark": {
+		kind:   transport.FieldText,
+		target: func(f *dto.Filter) *[]dto.Condition { return &f.Mark },
+	},
+	"model": {
+		kind:   transport.FieldText,
+		target: func(f *dto.Filter) *[]dto.Condition { return &f.Model },
+	},
+	"year": {
+		kind:   transport.FieldNumber,
+		target: func(f *dto.Filter) *[]dto.Condition { return &f.Year },
+	},
+	// Регион сравнивается точно: по подстроке 77 совпал бы и с 177
+	"region": {
+		kind:   transport.FieldEnum,
+		target: func(f *dto.Filter) *[]dto.Condition { return &f.Region },
+		valid:  plate.ValidRegion,
+	},
+	"vin": {
+		kind:      transport.FieldText,
+		target:    func(f *dto.Filter) *[]dto.Condition { return &f.VIN },
+		normalize: vin.Normalize,
+	},
+	"color": {
+		kind:   transport.FieldText,
+		target: func(f *dto.Filter) *[]dto.Condition { return &f.Color },
+	},
+	"bodyType": {
+		kind:      transport.FieldEnum,
+		target:    func(f *dto.Filter) *[]dto.Condition { return &f.BodyType },
+		normalize: strings.ToLower,
+		valid:     func(v string) bool { return dto.BodyType(v).Valid() },
+	},
+	"fuelType": {
+		kind:      transport.FieldEnum,
+		target:    func(f *dto.Filter) *[]dto.Condition { return &f.FuelType },
+		normalize: strings.ToLower,
+		valid:     func(v string) bool { return dto.FuelType(v).Valid() },
+	},
+	"engineVolume": {
+		kind:   transport.FieldNumber,
+		target: func(f *dto.Filter) *[]dto.Condition { return &f.EngineVolume },
+	},
+	"power": {
+		kind:   transport.FieldNumber,
+		target: func(f *dto.Filter) *[]dto.Condition { return &f.Power },
+	},
+	"mileage": {
+		kind:   transport.FieldNumber,
+		target: func(f *dto.Filter) *[]dto.Condition { return &f.Mileage },
+	},
+	"mileageAt": {
+		kind:   transport.FieldDate,
+		target: func(f *dto.Filter) *[]dto.Condition { return &f.MileageAt },
+	},
+	"ownerName": {
+		kind:   transport.FieldText,
+		target: func(f *dto.Filter) *[]dto.Condition { return &f.OwnerName },
+	},
+	"ownerSurname": {
+		kind:   transport.FieldText,
+		target: func(f *dto.Filter) *[]dto.Condition { return &f.OwnerSurname },
+	},
+	"ownerPatronymic": {
+		kind:   transport.FieldText,
+		target: func(f *dto.Filter) *[]dto.Condition { return &f.OwnerPatronymic },
+	},
+}
+
+// ParseFilter собирает фильтр из параметров запроса вида
+// "mark=Lada" или "year[between]=2010,2015". Параметры,
+// не относящиеся к фильтру, пропускаются. Ошибки всех параметров
+// добавляются в v, фильтр при них использовать нельзя
+func ParseFilter(
+	queries url.Values,
+	v *validate.Validator,
+) dto.Filter {
+
+	filter := dto.Filter{}
+
+	// Ошибки перечисляются в одном порядке при одинаковом запросе
+	keys := make([]string, 0, len(queries))
+	for key := range queries {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		values := queries[key]
+		name, operator := transport.SplitFilterKey(key)
+
+		field, ok := filterFields[name]
+		if !ok {
+			if operator != "" {
+				v.Add(key, "unknown filter")
+			}
+
+			continue
+		}
+
+		for _, value := range values {
+			// Пустое значение без оператора означает отсутствие фильтра
+			if operator == "" && strings.TrimSpace(value) == "" {
+				continue
+			}
+
+			condition, err := transport.ParseCondition(operator, value, field.kind)
+			if err != nil {
+				v.Add(key, err.Error())
+
+				continue
+			}
+
+			for i, value := range condition.Values {
+				if field.normalize != nil {
+					value = field.normalize(value)
+					condition.Values[i] = value
+				}
+
+				if field.valid != nil && !field.valid(value) {
+					v.Add(key, fmt.Sprintf("unknown value %q", value))
+				}
+			}
+
+			target := field.target(&filter)
+			*target = append(*target, condition)
+		}
+	}
+
+	return filter
+}
@@ -0,0 +1,46 @@
+package car
+
+import (
+	"github.com/jackvonhouse/car-enrichment/internal/dto"
+	"github.com/jackvonhouse/car-enrichment/pkg/validate"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestParseFilterRegion(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []dto.Condition
+		valid bool
+	}{
+		{"region=77", []dto.Condition{{Operator: dto.OperatorEq, Values: []string{"77"}}}, true},
+		{"region[in]=77,177", []dto.Condition{{Operator: dto.OperatorIn, Values: []string{"77", "177"}}}, true},
+		{"region[ne]=799", []dto.Condition{{Operator: dto.OperatorNe, Values: []string{"799"}}}, true},
+		{"region[like]=77", nil, false},
+		{"region[prefix]=7", nil, false},
+		{"region=7", nil, false},
+		{"region=00", nil, false},
+		{"region=moscow", nil, false},
+	}
+
+	for _, tt := range tests {
+		queries, err := url.ParseQuery(tt.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		v := validate.New()
+		filter := ParseFilter(queries, v)
+
+		if v.Valid() != tt.valid {
+			t.Errorf("%s: expected valid %t, got errors %v", tt.query, tt.valid, v.Err())
+
+			continue
+		}
+
+		if tt.valid && !reflect.DeepEqual(filter.Region, tt.want) {
+			t.Errorf("%s: expected %v, got %v", tt.query, tt.want, filter.Region)
+		}
+	}
+}
@@ -0,0 +1,416 @@
+package car
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"github.com/jackvonhouse/car-enrichment/internal/dto"
+	"github.com/jackvonhouse/car-enrichment/internal/errors"
+	"github.com/jackvonhouse/car-enrichment/internal/transport"
+	errpkg "github.com/jackvonhouse/car-enrichment/pkg/errors"
+	"github.com/jackvonhouse/car-enrichment/pkg/plate"
+	"github.com/jackvonhouse/car-enrichment/pkg/validate"
+	"github.com/jackvonhouse/car-enrichment/pkg/vin"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	importMaxSize = 10 << 20
+	importMaxRows = 10000
+	// Загрузка с обогащением идёт пачками и дольше обычного запроса
+	importTimeout = 5 * time.Minute
+)
+
+// importColumns — столбцы, которые разбираются при загрузке
+var importColumns = map[string]struct{}{
+	"regNum": {}, "mark": {}, "model": {}, "year": {}, "vin": {},
+	"color": {}, "bodyType": {}, "fuelType": {}, "engineVolume": {},
+	"power": {}, "mileage": {}, "mileageAt": {},
+	"ownerName": {}, "ownerSurname": {}, "ownerPatronymic": {},
+}
+
+// importIgnored — вычисляемые столбцы выгрузки, чтобы выгруженный
+// файл можно было загрузить обратно без правок
+var importIgnored = map[string]struct{}{
+	"id": {}, "region": {}, "regionSubject": {}, "ownerId": {},
+}
+
+// Import godoc
+// @Summary			Загрузить автомобили
+// @Description		Загрузка автомобилей из CSV: multipart/form-data с полем file или тело text/csv.
+// @Description		Первая строка — заголовок со столбцами как в выгрузке, обязателен regNum.
+// @Description		Строка с одним гос. номером обогащается из внешнего API, если передан enrich=true.
+// @Description		Полная строка требует mark, model, ownerName и ownerSurname,
+// @Description		остальные столбцы необязательны: year, vin, color, bodyType, fuelType, engineVolume,
+// @Description		power, mileage, mileageAt (2006-01-02), ownerPatronymic.
+// @Description		Столбцы id, region, regionSubject и ownerId выгрузки пропускаются.
+// @Description		Каждая строка проверяется и сохраняется отдельно, результат возвращается построчно.
+// @Description		С dryRun=true строки только проверяются, ничего не записывается и API не вызывается.
+// @Description		Обогащаемые номера списываются из суточной квоты, строки сверх квоты отклоняются
+// @Accept			multipart/form-data
+// @Accept			text/csv
+// @Produce			json
+// @Param			file formData file false "CSV-файл"
+// @Param			dryRun query bool false "Только проверить" default(false)
+// @Param			enrich query bool false "Обогащать строки с одним гос. номером" default(false)
+// @Param			delimiter query string false "Разделитель столбцов" default(,)
+// @Success			200 {object} dto.ImportResult
+// @Failure			400 {object} transport.Problem "Некорректный файл или заголовок"
+// @Failure			422 {object} transport.Problem "Некорректный параметр"
+// @Failure			413 {object} transport.Problem "Слишком большой файл"
+// @Failure			401 {object} transport.Problem "Нет ключа или токена, либо они недействительны"
+// @Failure			403 {object} transport.Problem "Недостаточно прав"
+// @Failure			500 {object} transport.Problem "Неизвестная ошибка"
+// @Security		ApiKeyAuth
+// @Security		BearerAuth
+// @Tags			Автомобиль
+// @Router /car/import [post]
+func (t Transport) Import(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+
+	queries := r.URL.Query()
+
+	v := validate.New()
+
+	options := dto.ImportOptions{
+		DryRun: transport.ParseBool(queries, "dryRun", v),
+		Enrich: transport.ParseBool(queries, "enrich", v),
+	}
+
+	delimiter := ','
+	if value := queries.Get("delimiter"); value != "" {
+		d, size := utf8.DecodeRuneInString(value)
+		v.Check("delimiter", size == len(value) && d != '"' && d != '\r' && d != '\n', "must be a single character other than quote or line break")
+
+		delimiter = d
+	}
+
+	if err := v.Err(); err != nil {
+		transport.ErrorFrom(w, err, transport.DefaultErrorHttpCodes)
+
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, importMaxSize)
+
+	body, err := t.importBody(r)
+	if err != nil {
+		transport.Error(w, importErrorCode(err), err.Error())
+
+		return
+	}
+
+	defer body.Close()
+
+	rows, err := t.importRows(body, delimiter)
+	if err != nil {
+		transport.Error(w, importErrorCode(err), err.Error())
+
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), importTimeout)
+	defer cancel()
+
+	result, err := t.car.Import(ctx, rows, options)
+	if err != nil {
+		t.logger.WithContext(r.Context()).Warn(err)
+
+		transport.ErrorFrom(w, err, transport.DefaultErrorHttpCodes)
+
+		return
+	}
+
+	transport.Response(w, result)
+}
+
+func importErrorCode(
+	err error,
+) int {
+
+	var maxBytesErr *http.MaxBytesError
+	if errpkg.As(err, &maxBytesErr) {
+		return http.StatusRequestEntityTooLarge
+	}
+
+	return http.StatusBadRequest
+}
+
+// importBody возвращает CSV из поля file формы или из тела запроса
+func (t Transport) importBody(
+	r *http.Request,
+) (io.ReadCloser, error) {
+
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, errors.ErrInvalid.New("invalid content type")
+	}
+
+	switch mediaType {
+
+	case "multipart/form-data":
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			return nil, t.importReadError(err)
+		}
+
+		return file, nil
+
+	case "text/csv", "text/plain":
+		return r.Body, nil
+	}
+
+	return nil, errors.ErrInvalid.New("unsupported content type " + mediaType)
+}
+
+// importRows разбирает заголовок и строки файла. Ошибка заголовка
+// отклоняет файл целиком, ошибка строки записывается в саму строку
+func (t Transport) importRows(
+	body io.Reader,
+	delimiter rune,
+) ([]dto.ImportRow, error) {
+
+	reader := csv.NewReader(body)
+	reader.Comma = delimiter
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.ErrInvalid.New("empty file")
+	}
+
+	if err != nil {
+		return nil, t.importReadError(err)
+	}
+
+	// Excel сохраняет CSV в UTF-8 с BOM
+	header[0] = strings.TrimPrefix(header[0], "\ufeff")
+
+	columns := make([]string, len(header))
+	seen := make(map[string]struct{}, len(header))
+
+	for i, name := range header {
+		name = strings.TrimSpace(name)
+
+		if _, ok := importIgnored[name]; ok {
+			continue
+		}
+
+		if _, ok := importColumns[name]; !ok {
+			return nil, errors.ErrInvalid.New(fmt.Sprintf("unknown column %q", name))
+		}
+
+		if _, ok := seen[name]; ok {
+			return nil, errors.ErrInvalid.New(fmt.Sprintf("duplicate column %q", name))
+		}
+
+		seen[name] = struct{}{}
+		columns[i] = name
+	}
+
+	if _, ok := seen["regNum"]; !ok {
+		return nil, errors.ErrInvalid.New("column regNum is required")
+	}
+
+	rows := make([]dto.ImportRow, 0)
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, t.importReadError(err)
+		}
+
+		line, _ := reader.FieldPos(0)
+
+		// Пустые строки в конце файла не считаются
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+
+		if len(rows) == importMaxRows {
+			return nil, errors.ErrInvalid.New(fmt.Sprintf("too many rows, max %d", importMaxRows))
+		}
+
+		if len(record) != len(header) {
+			rows = append(rows, dto.ImportRow{
+				Line:  line,
+				Error: fmt.Sprintf("expected %d fields, got %d", len(header), len(record)),
+			})
+
+			continue
+		}
+
+		values := make(map[string]string, len(columns))
+		for i, name := range columns {
+			if name == "" {
+				continue
+			}
+
+			if value := strings.TrimSpace(unescapeFormula(record[i])); value != "" {
+				values[name] = value
+			}
+		}
+
+		row := dto.ImportRow{Line: line}
+		row.Error = t.importCar(values, &row)
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+func (t Transport) importReadError(
+	err error,
+) error {
+
+	var maxBytesErr *http.MaxBytesError
+	if errpkg.As(err, &maxBytesErr) {
+		return err
+	}
+
+	if errpkg.Is(err, http.ErrMissingFile) {
+		return errors.ErrInvalid.New("file is required").Wrap(err)
+	}
+
+	return errors.ErrInvalid.New("invalid csv: " + err.Error()).Wrap(err)
+}
+
+// importCar заполняет автомобиль строки и возвращает текст ошибки,
+// проверки те же, что при создании и обновлении
+func (t Transport) importCar(
+	values map[string]string,
+	row *dto.ImportRow,
+) string {
+
+	car := &row.Car
+
+	regNum, ok := values["regNum"]
+	if !ok {
+		return "empty registration number"
+	}
+
+	// Номер в ответе остаётся исходным, пока не разобран
+	car.RegNum = regNum
+
+	p, err := plate.Parse(regNum)
+	if err != nil {
+		return "invalid registration number: " + regNum
+	}
+
+	car.RegNum = p.Number
+
+	if len(values) == 1 {
+		row.PlateOnly = true
+
+		return ""
+	}
+
+	missing := make([]string, 0)
+	for _, name := range []string{"mark", "model", "ownerName", "ownerSurname"} {
+		if _, ok := values[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+
+	if len(missing) != 0 {
+		return "incomplete record, missing " + strings.Join(missing, ", ")
+	}
+
+	car.Mark, car.Model = values["mark"], values["model"]
+
+	car.Owner = dto.Owner{
+		Name:       values["ownerName"],
+		Surname:    values["ownerSurname"],
+		Patronymic: values["ownerPatronymic"],
+	}
+
+	if value, ok := values["year"]; ok {
+		year, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Sprintf("invalid year: %q is not a number", value)
+		}
+
+		car.Year = year
+	}
+
+	if value, ok := values["vin"]; ok {
+		v, err := vin.Parse(value)
+		if err != nil {
+			return err.Error()
+		}
+
+		car.VIN = &v.Value
+	}
+
+	if value, ok := values["color"]; ok {
+		car.Color = &value
+	}
+
+	if value, ok := values["bodyType"]; ok {
+		bodyType := dto.BodyType(strings.ToLower(value))
+		car.BodyType = &bodyType
+	}
+
+	if value, ok := values["fuelType"]; ok {
+		fuelType := dto.FuelType(strings.ToLower(value))
+		car.FuelType = &fuelType
+	}
+
+	for name, target := range map[string]**int{
+		"engineVolume": &car.EngineVolume,
+		"power":        &car.Power,
+		"mileage":      &car.Mileage,
+	} {
+		value, ok := values[name]
+		if !ok {
+			continue
+		}
+
+		number, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Sprintf("invalid %s: %q is not a number", name, value)
+		}
+
+		*target = &number
+	}
+
+	if value, ok := values["mileageAt"]; ok {
+		mileageAt, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			mileageAt, err = time.Parse(time.RFC3339, value)
+		}
+
+		if err != nil {
+			return "invalid mileage date"
+		}
+
+		car.MileageAt = &mileageAt
+	}
+
+	if car.Mileage != nil && car.MileageAt == nil {
+		now := time.Now()
+		car.MileageAt = &now
+	}
+
+	if err := validateCar(*car); err != nil {
+		return err.Error()
+	}
+
+	normalizeCar(car)
+
+	return ""
+}
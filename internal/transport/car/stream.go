@@ -0,0 +1,81 @@
+package car
+
+import (
+	"encoding/json"
+	"github.com/jackvonhouse/car-enrichment/internal/dto"
+	"github.com/jackvonhouse/car-enrichment/internal/transport"
+	"net/http"
+)
+
+const ndjsonContentType = "application/x-ndjson"
+
+// Объектов между сбросами буфера в ответ
+const streamFlushEvery = 100
+
+// stream отдаёт все автомобили, подходящие под фильтр, по одному
+// JSON-объекту на строку. Строки читаются курсором и пишутся в ответ
+// по мере чтения, поэтому пагинация не нужна
+func (t Transport) stream(
+	w http.ResponseWriter,
+	r *http.Request,
+	filter dto.Filter,
+	sort []dto.Sort,
+) {
+
+	var (
+		encoder *json.Encoder
+		written int
+	)
+
+	flusher, _ := w.(http.Flusher)
+
+	err := t.car.Iterate(r.Context(), filter, sort, func(car dto.Car) error {
+		// Ответ начинается с первой строки, чтобы ошибка до неё
+		// ещё могла вернуться обычным ответом
+		if encoder == nil {
+			w.Header().Set("Content-Type", ndjsonContentType)
+			w.WriteHeader(http.StatusOK)
+
+			encoder = json.NewEncoder(w)
+		}
+
+		if err := encoder.Encode(car); err != nil {
+			return err
+		}
+
+		written++
+
+		if flusher != nil && written%streamFlushEvery == 0 {
+			flusher.Flush()
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		t.logger.WithContext(r.Context()).Warn(err)
+
+		if encoder != nil {
+			// Заголовки уже отправлены, остаётся оборвать поток,
+			// иначе клиент примет неполный ответ за полный
+			t.logger.WithContext(r.Context()).Warnf("stream interrupted after %d rows", written)
+
+			panic(http.ErrAbortHandler)
+		}
+
+		transport.ErrorFrom(w, err, transport.DefaultErrorHttpCodes)
+
+		return
+	}
+
+	if encoder == nil {
+		w.Header().Set("Content-Type", ndjsonContentType)
+		w.WriteHeader(http.StatusOK)
+
+		return
+	}
+
+	if flusher != nil {
+		flusher.Flush()
+	}
+}
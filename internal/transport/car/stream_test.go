@@ -0,0 +1,80 @@
+package car
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"github.com/jackvonhouse/car-enrichment/internal/dto"
+	"github.com/jackvonhouse/car-enrichment/internal/errors"
+	"github.com/jackvonhouse/car-enrichment/pkg/log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// iterateUseCase отдаёт rows автомобилей и затем ошибку err
+type iterateUseCase struct {
+	carUseCase
+
+	rows int
+	err  error
+}
+
+func (u iterateUseCase) Iterate(
+	_ context.Context,
+	_ dto.Filter,
+	_ []dto.Sort,
+	fn func(dto.Car) error,
+) error {
+
+	for i := 0; i < u.rows; i++ {
+		if err := fn(dto.Car{ID: int64(i + 1), RegNum: fmt.Sprintf("А%03dАА77", i)}); err != nil {
+			return err
+		}
+	}
+
+	return u.err
+}
+
+func TestStreamAbortsOnIterateError(t *testing.T) {
+	const rows = 3
+
+	transport := New(iterateUseCase{rows: rows, err: errors.ErrInternal.New("connection reset")}, log.NewLogrusLogger())
+
+	r := httptest.NewRequest(http.MethodGet, "/car", nil)
+	r.Header.Set("Accept", ndjsonContentType)
+
+	w := httptest.NewRecorder()
+
+	defer func() {
+		if value := recover(); value != http.ErrAbortHandler {
+			t.Fatalf("expected panic with http.ErrAbortHandler, got %v", value)
+		}
+
+		lines := 0
+		for scanner := bufio.NewScanner(w.Body); scanner.Scan(); {
+			lines++
+		}
+
+		if lines != rows {
+			t.Errorf("expected %d rows before abort, got %d", rows, lines)
+		}
+	}()
+
+	transport.Get(w, r)
+}
+
+func TestStreamErrorBeforeFirstRow(t *testing.T) {
+	transport := New(iterateUseCase{err: errors.ErrInternal.New("connection reset")}, log.NewLogrusLogger())
+
+	r := httptest.NewRequest(http.MethodGet, "/car", nil)
+	r.Header.Set("Accept", ndjsonContentType)
+
+	w := httptest.NewRecorder()
+
+	transport.Get(w, r)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected status %d, got %d", http.StatusInternalServerError, w.Code)
+	}
+}
@@ -0,0 +1,98 @@
+package car
+
+import (
+	"github.com/jackvonhouse/car-enrichment/internal/dto"
+	"github.com/jackvonhouse/car-enrichment/pkg/plate"
+	"github.com/jackvonhouse/car-enrichment/pkg/validate"
+	"github.com/jackvonhouse/car-enrichment/pkg/vin"
+	"strings"
+	"time"
+)
+
+const (
+	minYear = 1900
+
+	maxNameLength  = 100
+	maxColorLength = 50
+)
+
+// validateCreate проверяет все номера, а не только первый некорректный
+func validateCreate(
+	data dto.CreateCar,
+) error {
+
+	v := validate.New()
+
+	validate.Field(v, "reg_numbers", data.RegNumbers, validate.NotEmpty[string]())
+	validate.Each(v, "reg_numbers", data.RegNumbers,
+		validate.Required(),
+		validate.Parse(plate.Parse),
+	)
+
+	return v.Err()
+}
+
+// validateCar проверяет поля автомобиля при обновлении и импорте.
+// Пустые номер, марка, модель и год не меняют сохранённые значения
+func validateCar(
+	data dto.Car,
+) error {
+
+	v := validate.New()
+
+	if data.RegNum != "" {
+		validate.Field(v, "regNum", data.RegNum, validate.Parse(plate.Parse))
+	}
+
+	validate.Field(v, "owner.id", data.Owner.ID, validate.Min[int64](0))
+	validate.Field(v, "mark", data.Mark, validate.Length(0, maxNameLength))
+	validate.Field(v, "model", data.Model, validate.Length(0, maxNameLength))
+
+	if data.Year != 0 {
+		validate.Field(v, "year", data.Year, validate.Range(minYear, time.Now().Year()))
+	}
+
+	validate.Optional(v, "vin", data.VIN, validate.Parse(vin.Parse))
+
+	validate.Optional(v, "color", data.Color,
+		validate.Required(),
+		validate.Length(0, maxColorLength),
+	)
+
+	validate.Optional(v, "bodyType", data.BodyType, validate.OneOf(dto.BodyTypes...))
+	validate.Optional(v, "fuelType", data.FuelType, validate.OneOf(dto.FuelTypes...))
+
+	validate.Optional(v, "engineVolume", data.EngineVolume, validate.Min(1))
+	validate.Optional(v, "power", data.Power, validate.Min(1))
+	validate.Optional(v, "mileage", data.Mileage, validate.Min(0))
+
+	if data.MileageAt != nil {
+		v.Check("mileageAt", data.Mileage != nil, "requires mileage")
+		v.Check("mileageAt", !data.MileageAt.After(time.Now()), "must not be in the future")
+	}
+
+	return v.Err()
+}
+
+// normalizeCar приводит проверенные поля к виду, в котором они хранятся
+func normalizeCar(
+	data *dto.Car,
+) {
+
+	if data.RegNum != "" {
+		if p, err := plate.Parse(data.RegNum); err == nil {
+			data.RegNum = p.Number
+		}
+	}
+
+	if data.VIN != nil {
+		if v, err := vin.Parse(*data.VIN); err == nil {
+			data.VIN = &v.Value
+		}
+	}
+
+	if data.Color != nil {
+		color := strings.TrimSpace(*data.Color)
+		data.Color = &color
+	}
+}
@@ -0,0 +1,56 @@
+package car
+
+import (
+	"encoding/json"
+	"github.com/jackvonhouse/car-enrichment/internal/transport"
+	"github.com/jackvonhouse/car-enrichment/pkg/log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Некорректные параметры запроса возвращаются одинаково:
+// 422 с ошибкой в поле параметра
+func TestQueryParamsValidation(t *testing.T) {
+	tr := New(iterateUseCase{}, log.NewLogrusLogger())
+
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		method  string
+		target  string
+		field   string
+	}{
+		{"limit above max", tr.Get, http.MethodGet, "/car?limit=100000000", "limit"},
+		{"zero limit", tr.Get, http.MethodGet, "/car?limit=0", "limit"},
+		{"unknown sort field", tr.Get, http.MethodGet, "/car?sort=color", "sort"},
+		{"empty sort field", tr.Get, http.MethodGet, "/car?sort=year,", "sort"},
+		{"duplicate sort field", tr.Get, http.MethodGet, "/car?sort=year,-year", "sort"},
+		{"export format", tr.Export, http.MethodGet, "/car/export?format=pdf", "format"},
+		{"export column", tr.Export, http.MethodGet, "/car/export?columns=regNum,price", "columns"},
+		{"export sort", tr.Export, http.MethodGet, "/car/export?sort=-", "sort"},
+		{"import dryRun", tr.Import, http.MethodPost, "/car/import?dryRun=maybe", "dryRun"},
+		{"import enrich", tr.Import, http.MethodPost, "/car/import?enrich=2", "enrich"},
+		{"import delimiter", tr.Import, http.MethodPost, "/car/import?delimiter=%22", "delimiter"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			tt.handler(w, httptest.NewRequest(tt.method, tt.target, nil))
+
+			if w.Code != http.StatusUnprocessableEntity {
+				t.Fatalf("expected status %d, got %d: %s", http.StatusUnprocessableEntity, w.Code, w.Body)
+			}
+
+			problem := transport.Problem{}
+			if err := json.NewDecoder(w.Body).Decode(&problem); err != nil {
+				t.Fatal(err)
+			}
+
+			if len(problem.Errors) != 1 || problem.Errors[0].Field != tt.field {
+				t.Errorf("expected one error in %s, got %v", tt.field, problem.Errors)
+			}
+		})
+	}
+}
@@ -0,0 +1,554 @@
+package dictionary
+
+import (
+	"context"
+	"github.com/gorilla/mux"
+	"github.com/jackvonhouse/car-enrichment/internal/dto"
+	"github.com/jackvonhouse/car-enrichment/internal/identity"
+	"github.com/jackvonhouse/car-enrichment/internal/transport"
+	"github.com/jackvonhouse/car-enrichment/internal/transport/router"
+	"github.com/jackvonhouse/car-enrichment/pkg/log"
+	"github.com/jackvonhouse/car-enrichment/pkg/validate"
+	"net/http"
+	"time"
+)
+
+type dictionaryUseCase interface {
+	GetMarks(context.Context) ([]dto.Mark, error)
+	GetModels(context.Context, int64) ([]dto.Model, error)
+
+	CreateMark(context.Context, dto.CreateMark) (int64, error)
+	CreateModel(context.Context, dto.CreateModel) (int64, error)
+
+	AddMarkAlias(context.Context, int64, string) error
+	AddModelAlias(context.Context, int64, string) error
+
+	DeleteMark(context.Context, int64) error
+	DeleteModel(context.Context, int64) error
+
+	DeleteMarkAlias(context.Context, int64, string) error
+	DeleteModelAlias(context.Context, int64, string) error
+}
+
+type Transport struct {
+	dictionary dictionaryUseCase
+
+	logger log.Logger
+}
+
+func New(
+	dictionary dictionaryUseCase,
+	logger log.Logger,
+) Transport {
+	return Transport{
+		dictionary: dictionary,
+		logger:     logger.WithField("unit", "dictionary"),
+	}
+}
+
+func (t Transport) Handle(
+	r *mux.Router,
+) {
+	r.Handle("/mark", router.Scoped(identity.ScopeRead, t.GetMarks)).
+		Methods(http.MethodGet)
+
+	r.Handle("/mark", router.Scoped(identity.ScopeAdmin, t.CreateMark)).
+		Methods(http.MethodPost)
+
+	r.Handle("/mark/{id:[0-9]+}", router.Scoped(identity.ScopeAdmin, t.DeleteMark)).
+		Methods(http.MethodDelete)
+
+	r.Handle("/mark/{id:[0-9]+}/alias", router.Scoped(identity.ScopeAdmin, t.AddMarkAlias)).
+		Methods(http.MethodPost)
+
+	r.Handle("/mark/{id:[0-9]+}/alias/{alias}", router.Scoped(identity.ScopeAdmin, t.DeleteMarkAlias)).
+		Methods(http.MethodDelete)
+
+	r.Handle("/mark/{id:[0-9]+}/model", router.Scoped(identity.ScopeRead, t.GetModels)).
+		Methods(http.MethodGet)
+
+	r.Handle("/mark/{id:[0-9]+}/model", router.Scoped(identity.ScopeAdmin, t.CreateModel)).
+		Methods(http.MethodPost)
+
+	r.Handle("/model/{id:[0-9]+}", router.Scoped(identity.ScopeAdmin, t.DeleteModel)).
+		Methods(http.MethodDelete)
+
+	r.Handle("/model/{id:[0-9]+}/alias", router.Scoped(identity.ScopeAdmin, t.AddModelAlias)).
+		Methods(http.MethodPost)
+
+	r.Handle("/model/{id:[0-9]+}/alias/{alias}", router.Scoped(identity.ScopeAdmin, t.DeleteModelAlias)).
+		Methods(http.MethodDelete)
+}
+
+func (t Transport) error(
+	w http.ResponseWriter,
+	r *http.Request,
+	err error,
+) {
+
+	t.logger.WithContext(r.Context()).Warn(err)
+
+	transport.ErrorFrom(w, err, transport.DefaultErrorHttpCodes)
+}
+
+func (t Transport) id(
+	r *http.Request,
+) (int64, bool) {
+
+	id, err := transport.StringToInt(mux.Vars(r)["id"])
+	if err != nil || id <= 0 {
+		return 0, false
+	}
+
+	return int64(id), true
+}
+
+// GetMarks godoc
+// @Summary			Получить марки
+// @Description		Получение справочника марок с синонимами
+// @Accept			json
+// @Produce			json
+// @Success			200 {array} dto.Mark
+// @Failure			500 {object} transport.Problem "Неизвестная ошибка"
+// @Security		ApiKeyAuth
+// @Security		BearerAuth
+// @Tags			Справочник
+// @Router /dictionary/mark [get]
+func (t Transport) GetMarks(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	marks, err := t.dictionary.GetMarks(ctx)
+	if err != nil {
+		t.error(w, r, err)
+
+		return
+	}
+
+	transport.Response(w, marks)
+}
+
+// CreateMark godoc
+// @Summary			Добавить марку
+// @Description		Добавление марки в справочник. Название и синонимы сравниваются
+// @Description		без учёта регистра, диакритики, пробелов и дефисов
+// @Accept			json
+// @Produce			json
+// @Param			request body dto.CreateMark true "Марка"
+// @Success			200 {object} object{id=int}
+// @Failure			400 {object} transport.Problem "Некорректный JSON"
+// @Failure			422 {object} transport.Problem "Пустое название"
+// @Failure			409 {object} transport.Problem "Марка или синоним уже существует"
+// @Failure			403 {object} transport.Problem "Нет права admin"
+// @Failure			500 {object} transport.Problem "Неизвестная ошибка"
+// @Security		ApiKeyAuth
+// @Security		BearerAuth
+// @Tags			Справочник
+// @Router /dictionary/mark [post]
+func (t Transport) CreateMark(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+
+	data := dto.CreateMark{}
+
+	if err := transport.DecodeJSON(r, &data); err != nil {
+		transport.ErrorFrom(w, err, transport.DefaultErrorHttpCodes)
+
+		return
+	}
+
+	v := validate.New()
+	validate.Field(v, "name", data.Name, validate.Required())
+
+	if err := v.Err(); err != nil {
+		transport.ErrorFrom(w, err, transport.DefaultErrorHttpCodes)
+
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	markId, err := t.dictionary.CreateMark(ctx, data)
+	if err != nil {
+		t.error(w, r, err)
+
+		return
+	}
+
+	transport.Response(w, map[string]any{"id": markId})
+}
+
+// DeleteMark godoc
+// @Summary			Удалить марку
+// @Description		Удаление марки вместе с моделями и синонимами.
+// @Description		Автомобили этой марки становятся неизвестными для справочника
+// @Accept			json
+// @Produce			json
+// @Param			id path int true "Идентификатор марки"
+// @Success			200 {object} object{result=bool}
+// @Failure			404 {object} transport.Problem "Марка не найдена"
+// @Failure			403 {object} transport.Problem "Нет права admin"
+// @Failure			500 {object} transport.Problem "Неизвестная ошибка"
+// @Security		ApiKeyAuth
+// @Security		BearerAuth
+// @Tags			Справочник
+// @Router /dictionary/mark/{id} [delete]
+func (t Transport) DeleteMark(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+
+	markId, ok := t.id(r)
+	if !ok {
+		transport.Error(w, http.StatusBadRequest, "invalid mark id")
+
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	if err := t.dictionary.DeleteMark(ctx, markId); err != nil {
+		t.error(w, r, err)
+
+		return
+	}
+
+	transport.Response(w, map[string]any{"success": true})
+}
+
+// AddMarkAlias godoc
+// @Summary			Добавить синоним марки
+// @Accept			json
+// @Produce			json
+// @Param			id path int true "Идентификатор марки"
+// @Param			request body dto.CreateAlias true "Синоним"
+// @Success			200 {object} object{result=bool}
+// @Failure			400 {object} transport.Problem "Некорректный JSON"
+// @Failure			422 {object} transport.Problem "Пустой синоним"
+// @Failure			404 {object} transport.Problem "Марка не найдена"
+// @Failure			409 {object} transport.Problem "Синоним уже существует"
+// @Failure			403 {object} transport.Problem "Нет права admin"
+// @Failure			500 {object} transport.Problem "Неизвестная ошибка"
+// @Security		ApiKeyAuth
+// @Security		BearerAuth
+// @Tags			Справочник
+// @Router /dictionary/mark/{id}/alias [post]
+func (t Transport) AddMarkAlias(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+
+	markId, ok := t.id(r)
+	if !ok {
+		transport.Error(w, http.StatusBadRequest, "invalid mark id")
+
+		return
+	}
+
+	data := dto.CreateAlias{}
+
+	if err := transport.DecodeJSON(r, &data); err != nil {
+		transport.ErrorFrom(w, err, transport.DefaultErrorHttpCodes)
+
+		return
+	}
+
+	v := validate.New()
+	validate.Field(v, "alias", data.Alias, validate.Required())
+
+	if err := v.Err(); err != nil {
+		transport.ErrorFrom(w, err, transport.DefaultErrorHttpCodes)
+
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	if err := t.dictionary.AddMarkAlias(ctx, markId, data.Alias); err != nil {
+		t.error(w, r, err)
+
+		return
+	}
+
+	transport.Response(w, map[string]any{"success": true})
+}
+
+// DeleteMarkAlias godoc
+// @Summary			Удалить синоним марки
+// @Accept			json
+// @Produce			json
+// @Param			id path int true "Идентификатор марки"
+// @Param			alias path string true "Синоним"
+// @Success			200 {object} object{result=bool}
+// @Failure			404 {object} transport.Problem "Синоним не найден"
+// @Failure			403 {object} transport.Problem "Нет права admin"
+// @Failure			500 {object} transport.Problem "Неизвестная ошибка"
+// @Security		ApiKeyAuth
+// @Security		BearerAuth
+// @Tags			Справочник
+// @Router /dictionary/mark/{id}/alias/{alias} [delete]
+func (t Transport) DeleteMarkAlias(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+
+	markId, ok := t.id(r)
+	if !ok {
+		transport.Error(w, http.StatusBadRequest, "invalid mark id")
+
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	if err := t.dictionary.DeleteMarkAlias(ctx, markId, mux.Vars(r)["alias"]); err != nil {
+		t.error(w, r, err)
+
+		return
+	}
+
+	transport.Response(w, map[string]any{"success": true})
+}
+
+// GetModels godoc
+// @Summary			Получить модели марки
+// @Description		Получение моделей марки с синонимами
+// @Accept			json
+// @Produce			json
+// @Param			id path int true "Идентификатор марки"
+// @Success			200 {array} dto.Model
+// @Failure			500 {object} transport.Problem "Неизвестная ошибка"
+// @Security		ApiKeyAuth
+// @Security		BearerAuth
+// @Tags			Справочник
+// @Router /dictionary/mark/{id}/model [get]
+func (t Transport) GetModels(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+
+	markId, ok := t.id(r)
+	if !ok {
+		transport.Error(w, http.StatusBadRequest, "invalid mark id")
+
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	models, err := t.dictionary.GetModels(ctx, markId)
+	if err != nil {
+		t.error(w, r, err)
+
+		return
+	}
+
+	transport.Response(w, models)
+}
+
+// CreateModel godoc
+// @Summary			Добавить модель
+// @Description		Добавление модели марки в справочник
+// @Accept			json
+// @Produce			json
+// @Param			id path int true "Идентификатор марки"
+// @Param			request body dto.CreateModel true "Модель"
+// @Success			200 {object} object{id=int}
+// @Failure			400 {object} transport.Problem "Некорректный JSON"
+// @Failure			422 {object} transport.Problem "Пустое название"
+// @Failure			404 {object} transport.Problem "Марка не найдена"
+// @Failure			409 {object} transport.Problem "Модель или синоним уже существует"
+// @Failure			403 {object} transport.Problem "Нет права admin"
+// @Failure			500 {object} transport.Problem "Неизвестная ошибка"
+// @Security		ApiKeyAuth
+// @Security		BearerAuth
+// @Tags			Справочник
+// @Router /dictionary/mark/{id}/model [post]
+func (t Transport) CreateModel(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+
+	markId, ok := t.id(r)
+	if !ok {
+		transport.Error(w, http.StatusBadRequest, "invalid mark id")
+
+		return
+	}
+
+	data := dto.CreateModel{}
+
+	if err := transport.DecodeJSON(r, &data); err != nil {
+		transport.ErrorFrom(w, err, transport.DefaultErrorHttpCodes)
+
+		return
+	}
+
+	v := validate.New()
+	validate.Field(v, "name", data.Name, validate.Required())
+
+	if err := v.Err(); err != nil {
+		transport.ErrorFrom(w, err, transport.DefaultErrorHttpCodes)
+
+		return
+	}
+
+	data.MarkID = markId
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	modelId, err := t.dictionary.CreateModel(ctx, data)
+	if err != nil {
+		t.error(w, r, err)
+
+		return
+	}
+
+	transport.Response(w, map[string]any{"id": modelId})
+}
+
+// DeleteModel godoc
+// @Summary			Удалить модель
+// @Description		Удаление модели вместе с синонимами.
+// @Description		Автомобили этой модели становятся неизвестными для справочника
+// @Accept			json
+// @Produce			json
+// @Param			id path int true "Идентификатор модели"
+// @Success			200 {object} object{result=bool}
+// @Failure			404 {object} transport.Problem "Модель не найдена"
+// @Failure			403 {object} transport.Problem "Нет права admin"
+// @Failure			500 {object} transport.Problem "Неизвестная ошибка"
+// @Security		ApiKeyAuth
+// @Security		BearerAuth
+// @Tags			Справочник
+// @Router /dictionary/model/{id} [delete]
+func (t Transport) DeleteModel(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+
+	modelId, ok := t.id(r)
+	if !ok {
+		transport.Error(w, http.StatusBadRequest, "invalid model id")
+
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	if err := t.dictionary.DeleteModel(ctx, modelId); err != nil {
+		t.error(w, r, err)
+
+		return
+	}
+
+	transport.Response(w, map[string]any{"success": true})
+}
+
+// AddModelAlias godoc
+// @Summary			Добавить синоним модели
+// @Accept			json
+// @Produce			json
+// @Param			id path int true "Идентификатор модели"
+// @Param			request body dto.CreateAlias true "Синоним"
+// @Success			200 {object} object{result=bool}
+// @Failure			400 {object} transport.Problem "Некорректный JSON"
+// @Failure			422 {object} transport.Problem "Пустой синоним"
+// @Failure			404 {object} transport.Problem "Модель не найдена"
+// @Failure			409 {object} transport.Problem "Синоним уже существует"
+// @Failure			403 {object} transport.Problem "Нет права admin"
+// @Failure			500 {object} transport.Problem "Неизвестная ошибка"
+// @Security		ApiKeyAuth
+// @Security		BearerAuth
+// @Tags			Справочник
+// @Router /dictionary/model/{id}/alias [post]
+func (t Transport) AddModelAlias(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+
+	modelId, ok := t.id(r)
+	if !ok {
+		transport.Error(w, http.StatusBadRequest, "invalid model id")
+
+		return
+	}
+
+	data := dto.CreateAlias{}
+
+	if err := transport.DecodeJSON(r, &data); err != nil {
+		transport.ErrorFrom(w, err, transport.DefaultErrorHttpCodes)
+
+		return
+	}
+
+	v := validate.New()
+	validate.Field(v, "alias", data.Alias, validate.Required())
+
+	if err := v.Err(); err != nil {
+		transport.ErrorFrom(w, err, transport.DefaultErrorHttpCodes)
+
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	if err := t.dictionary.AddModelAlias(ctx, modelId, data.Alias); err != nil {
+		t.error(w, r, err)
+
+		return
+	}
+
+	transport.Response(w, map[string]any{"success": true})
+}
+
+// DeleteModelAlias godoc
+// @Summary			Удалить синоним модели
+// @Accept			json
+// @Produce			json
+// @Param			id path int true "Идентификатор модели"
+// @Param			alias path string true "Синоним"
+// @Success			200 {object} object{result=bool}
+// @Failure			404 {object} transport.Problem "Синоним не найден"
+// @Failure			403 {object} transport.Problem "Нет права admin"
+// @Failure			500 {object} transport.Problem "Неизвестная ошибка"
+// @Security		ApiKeyAuth
+// @Security		BearerAuth
+// @Tags			Справочник
+// @Router /dictionary/model/{id}/alias/{alias} [delete]
+func (t Transport) DeleteModelAlias(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+
+	modelId, ok := t.id(r)
+	if !ok {
+		transport.Error(w, http.StatusBadRequest, "invalid model id")
+
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	if err := t.dictionary.DeleteModelAlias(ctx, modelId, mux.Vars(r)["alias"]); err != nil {
+		t.error(w, r, err)
+
+		return
+	}
+
+	transport.Response(w, map[string]any{"success": true})
+}
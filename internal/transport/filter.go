@@ -0,0 +1,140 @@
+package transport
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackvonhouse/car-enrichment/internal/dto"
+	"github.com/jackvonhouse/car-enrichment/internal/errors"
+)
+
+type FieldKind int
+
+const (
+	FieldText FieldKind = iota
+	FieldNumber
+	// FieldEnum — значение из фиксированного набора
+	FieldEnum
+	// FieldDate — дата в формате 2006-01-02
+	FieldDate
+)
+
+const dateLayout = "2006-01-02"
+
+var (
+	defaultOperators = map[FieldKind]dto.Operator{
+		FieldText:   dto.OperatorLike,
+		FieldNumber: dto.OperatorEq,
+		FieldEnum:   dto.OperatorEq,
+		FieldDate:   dto.OperatorEq,
+	}
+
+	allowedOperators = map[FieldKind]map[dto.Operator]struct{}{
+		FieldText: {
+			dto.OperatorEq:      {},
+			dto.OperatorNe:      {},
+			dto.OperatorIn:      {},
+			dto.OperatorLike:    {},
+			dto.OperatorPrefix:  {},
+			dto.OperatorSimilar: {},
+		},
+		FieldNumber: {
+			dto.OperatorEq:      {},
+			dto.OperatorNe:      {},
+			dto.OperatorIn:      {},
+			dto.OperatorGte:     {},
+			dto.OperatorLte:     {},
+			dto.OperatorBetween: {},
+		},
+		FieldEnum: {
+			dto.OperatorEq: {},
+			dto.OperatorNe: {},
+			dto.OperatorIn: {},
+		},
+		FieldDate: {
+			dto.OperatorEq:      {},
+			dto.OperatorGte:     {},
+			dto.OperatorLte:     {},
+			dto.OperatorBetween: {},
+		},
+	}
+)
+
+// SplitFilterKey разбирает ключ фильтра вида "year[gte]".
+// Для ключа без оператора возвращает пустой оператор
+func SplitFilterKey(
+	key string,
+) (string, dto.Operator) {
+
+	open := strings.IndexByte(key, '[')
+	if open < 0 || !strings.HasSuffix(key, "]") {
+		return key, ""
+	}
+
+	return key[:open], dto.Operator(key[open+1 : len(key)-1])
+}
+
+// ParseCondition проверяет оператор и значение фильтра.
+// Значения операторов in и between перечисляются через запятую
+func ParseCondition(
+	operator dto.Operator,
+	value string,
+	kind FieldKind,
+) (dto.Condition, error) {
+
+	if operator == "" {
+		operator = defaultOperators[kind]
+	}
+
+	if _, ok := allowedOperators[kind][operator]; !ok {
+		return dto.Condition{}, errors.ErrInvalid.New(
+			fmt.Sprintf("unsupported operator %q", operator),
+		)
+	}
+
+	values := []string{value}
+	if operator == dto.OperatorIn || operator == dto.OperatorBetween {
+		values = strings.Split(value, ",")
+	}
+
+	if operator == dto.OperatorBetween && len(values) != 2 {
+		return dto.Condition{}, errors.ErrInvalid.New(
+			"between requires two values",
+		)
+	}
+
+	for i, v := range values {
+		v = strings.TrimSpace(v)
+
+		if v == "" {
+			return dto.Condition{}, errors.ErrInvalid.New(
+				"empty value",
+			)
+		}
+
+		if kind == FieldNumber {
+			if _, err := strconv.Atoi(v); err != nil {
+				return dto.Condition{}, errors.ErrInvalid.New(
+					fmt.Sprintf("%q is not a number", v),
+				)
+			}
+		}
+
+		if kind == FieldDate {
+			if _, err := time.Parse(dateLayout, v); err != nil {
+				return dto.Condition{}, errors.ErrInvalid.New(
+					fmt.Sprintf("%q is not a date", v),
+				)
+			}
+		}
+
+		values[i] = v
+	}
+
+	return dto.Condition{
+		Operator: operator,
+		Values:   values,
+	}, nil
+}
@@ -0,0 +1,86 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/gorilla/mux"
+	"github.com/jackvonhouse/car-enrichment/internal/dto"
+	"github.com/jackvonhouse/car-enrichment/pkg/log"
+	"net/http"
+	"time"
+)
+
+type healthUseCase interface {
+	Live(context.Context) dto.Health
+	Ready(context.Context) dto.Health
+}
+
+type Transport struct {
+	health healthUseCase
+
+	logger log.Logger
+}
+
+func New(
+	health healthUseCase,
+	logger log.Logger,
+) Transport {
+	return Transport{
+		health: health,
+		logger: logger.WithField("unit", "health"),
+	}
+}
+
+// Handle объявляет проверки в корне, вне /api/v1 и swagger: они не требуют
+// ключа и не учитываются в ограничении частоты запросов
+func (t Transport) Handle(
+	r *mux.Router,
+) {
+	r.HandleFunc("/healthz", t.Live).
+		Methods(http.MethodGet, http.MethodHead)
+
+	r.HandleFunc("/readyz", t.Ready).
+		Methods(http.MethodGet, http.MethodHead)
+}
+
+// Live отвечает, что процесс запущен. Зависимости не проверяются,
+// чтобы сбой базы не приводил к перезапуску сервиса
+func (t Transport) Live(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+
+	t.response(w, r, t.health.Live(r.Context()))
+}
+
+// Ready отвечает 503, если зависимость недоступна или сервис останавливается
+func (t Transport) Ready(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	t.response(w, r, t.health.Ready(ctx))
+}
+
+func (t Transport) response(
+	w http.ResponseWriter,
+	r *http.Request,
+	health dto.Health,
+) {
+
+	code := http.StatusOK
+	if health.Status != dto.HealthStatusOk {
+		code = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+
+	if err := json.NewEncoder(w).Encode(health); err != nil {
+		t.logger.WithContext(r.Context()).Warnf("can't encode health: %s", err)
+	}
+}
@@ -0,0 +1,109 @@
+package transport
+
+import (
+	"net/http"
+	"strconv"
+)
+
+type Page[T any] struct {
+	Items      []T     `json:"items"`
+	Total      int64   `json:"total"`
+	Limit      int     `json:"limit"`
+	Offset     int     `json:"offset"`
+	NextCursor *string `json:"nextCursor"`
+	Next       *string `json:"next"`
+	Prev       *string `json:"prev"`
+}
+
+func NewPage[T any](
+	r *http.Request,
+	items []T,
+	total int64,
+	limit, offset int,
+) Page[T] {
+
+	page := Page[T]{
+		Items:  items,
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+	}
+
+	if int64(offset+limit) < total {
+		page.Next = pageLink(r, offset+limit)
+	}
+
+	if offset > 0 {
+		page.Prev = pageLink(r, max(offset-limit, 0))
+	}
+
+	return page
+}
+
+// NewCursorPage строит страницу для пагинации по курсору.
+// Переход назад по курсору не поддерживается, поэтому Prev всегда пуст
+func NewCursorPage[T any](
+	r *http.Request,
+	items []T,
+	total int64,
+	limit int,
+	nextCursor string,
+) Page[T] {
+
+	page := Page[T]{
+		Items: items,
+		Total: total,
+		Limit: limit,
+	}
+
+	if nextCursor != "" {
+		page.NextCursor = &nextCursor
+		page.Next = cursorLink(r, nextCursor)
+	}
+
+	return page
+}
+
+func (p Page[T]) WithNextCursor(
+	nextCursor string,
+) Page[T] {
+
+	if nextCursor != "" {
+		p.NextCursor = &nextCursor
+	}
+
+	return p
+}
+
+func pageLink(
+	r *http.Request,
+	offset int,
+) *string {
+
+	u := *r.URL
+
+	queries := u.Query()
+	queries.Set("offset", strconv.Itoa(offset))
+	u.RawQuery = queries.Encode()
+
+	link := u.RequestURI()
+
+	return &link
+}
+
+func cursorLink(
+	r *http.Request,
+	cursor string,
+) *string {
+
+	u := *r.URL
+
+	queries := u.Query()
+	queries.Del("offset")
+	queries.Set("cursor", cursor)
+	u.RawQuery = queries.Encode()
+
+	link := u.RequestURI()
+
+	return &link
+}
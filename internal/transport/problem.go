@@ -0,0 +1,211 @@
+package transport
+
+import (
+	"encoding/json"
+	"github.com/jackvonhouse/car-enrichment/config"
+	"github.com/jackvonhouse/car-enrichment/internal/errors"
+	"github.com/jackvonhouse/car-enrichment/internal/requestid"
+	errpkg "github.com/jackvonhouse/car-enrichment/pkg/errors"
+	"github.com/jackvonhouse/car-enrichment/pkg/validate"
+	"net/http"
+	"strings"
+)
+
+const ProblemContentType = "application/problem+json"
+
+// problemTypeBase — начало type в ответе, код ошибки дописывается в конец
+const problemTypeBase = "urn:car-enrichment:problem:"
+
+// ProblemType — постоянный код и заголовок ошибки. Клиенты
+// различают ошибки по коду, поэтому коды нельзя менять
+type ProblemType struct {
+	Code  string
+	Title string
+}
+
+var (
+	ProblemInvalid          = ProblemType{"invalid", "Invalid request"}
+	ProblemValidation       = ProblemType{"validation-failed", "Validation failed"}
+	ProblemInternal         = ProblemType{"internal", "Internal error"}
+	ProblemNotFound         = ProblemType{"not-found", "Not found"}
+	ProblemAlreadyExists    = ProblemType{"already-exists", "Already exists"}
+	ProblemFailed           = ProblemType{"failed", "Operation failed"}
+	ProblemUnauthorized     = ProblemType{"unauthorized", "Unauthorized"}
+	ProblemForbidden        = ProblemType{"forbidden", "Forbidden"}
+	ProblemLimitExceeded    = ProblemType{"limit-exceeded", "Limit exceeded"}
+	ProblemTooLarge         = ProblemType{"too-large", "Request too large"}
+	ProblemUnsupportedMedia = ProblemType{"unsupported-media-type", "Unsupported media type"}
+	ProblemNotAcceptable    = ProblemType{"not-acceptable", "Not acceptable"}
+)
+
+// DefaultProblemTypes — коды ошибок по типам из internal/errors
+var DefaultProblemTypes = map[uint32]ProblemType{
+	errors.ErrInternal.TypeId:      ProblemInternal,
+	errors.ErrAlreadyExists.TypeId: ProblemAlreadyExists,
+	errors.ErrNotFound.TypeId:      ProblemNotFound,
+	errors.ErrInvalid.TypeId:       ProblemInvalid,
+	errors.ErrFailed.TypeId:        ProblemFailed,
+	errors.ErrUnauthorized.TypeId:  ProblemUnauthorized,
+	errors.ErrForbidden.TypeId:     ProblemForbidden,
+	errors.ErrLimitExceeded.TypeId: ProblemLimitExceeded,
+}
+
+// statusProblemTypes — коды ошибок, которые обработчики отдают
+// напрямую по статусу, без ошибки из нижних слоёв
+var statusProblemTypes = map[int]ProblemType{
+	http.StatusBadRequest:            ProblemInvalid,
+	http.StatusUnauthorized:          ProblemUnauthorized,
+	http.StatusForbidden:             ProblemForbidden,
+	http.StatusNotFound:              ProblemNotFound,
+	http.StatusNotAcceptable:         ProblemNotAcceptable,
+	http.StatusConflict:              ProblemAlreadyExists,
+	http.StatusRequestEntityTooLarge: ProblemTooLarge,
+	http.StatusUnsupportedMediaType:  ProblemUnsupportedMedia,
+	http.StatusUnprocessableEntity:   ProblemValidation,
+	http.StatusTooManyRequests:       ProblemLimitExceeded,
+	http.StatusInternalServerError:   ProblemInternal,
+}
+
+// FieldError — ошибка в отдельном поле запроса
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+} //@name FieldError
+
+// Problem — ответ с ошибкой по RFC 7807
+type Problem struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Detail    string       `json:"detail,omitempty"`
+	Code      string       `json:"code"`
+	RequestID string       `json:"requestId,omitempty"`
+	Errors    []FieldError `json:"errors,omitempty"`
+} //@name Problem
+
+var legacyErrors = false
+
+// SetErrorFormat выбирает формат ответов с ошибкой для всего сервиса
+func SetErrorFormat(
+	format string,
+) {
+
+	legacyErrors = format == config.ErrorFormatLegacy
+}
+
+// ErrorFrom отвечает ошибкой из нижних слоёв: статус берётся из codes,
+// код ошибки — из её типа. Подробности внутренних ошибок не раскрываются,
+// ошибки проверки полей отдаются списком со статусом 422
+func ErrorFrom(
+	w http.ResponseWriter,
+	err error,
+	codes map[uint32]int,
+) {
+
+	var invalid validate.Errors
+	if errpkg.As(err, &invalid) {
+		ValidationError(w, invalid)
+
+		return
+	}
+
+	code, msg := ErrorToHttpResponse(err, codes)
+
+	problemType, ok := DefaultProblemTypes[errpkg.TypeId(err)]
+	if !ok || code == http.StatusInternalServerError {
+		problemType = ProblemInternal
+	}
+
+	writeProblem(w, code, problemType, msg, nil)
+}
+
+// ValidationError отвечает 422 со списком ошибок по полям
+func ValidationError(
+	w http.ResponseWriter,
+	invalid validate.Errors,
+) {
+
+	fields := make([]FieldError, 0, len(invalid))
+	for _, err := range invalid {
+		fields = append(fields, FieldError{
+			Field:   err.Field,
+			Message: err.Message,
+		})
+	}
+
+	writeProblem(
+		w,
+		http.StatusUnprocessableEntity,
+		ProblemValidation,
+		invalid.Error(),
+		fields,
+	)
+}
+
+func statusProblemType(
+	statusCode int,
+) ProblemType {
+
+	if problemType, ok := statusProblemTypes[statusCode]; ok {
+		return problemType
+	}
+
+	text := http.StatusText(statusCode)
+
+	return ProblemType{
+		Code:  strings.ReplaceAll(strings.ToLower(text), " ", "-"),
+		Title: text,
+	}
+}
+
+// writeProblem пишет ошибку в выбранном формате. Идентификатор запроса
+// берётся из заголовка ответа, который выставляет router.RequestID
+func writeProblem(
+	w http.ResponseWriter,
+	statusCode int,
+	problemType ProblemType,
+	detail string,
+	fields []FieldError,
+) {
+
+	if legacyErrors {
+		writeLegacyError(w, statusCode, detail)
+
+		return
+	}
+
+	if detail == http.StatusText(statusCode) {
+		detail = ""
+	}
+
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(statusCode)
+
+	json.NewEncoder(w).Encode(Problem{
+		Type:      problemTypeBase + problemType.Code,
+		Title:     problemType.Title,
+		Status:    statusCode,
+		Detail:    detail,
+		Code:      problemType.Code,
+		RequestID: w.Header().Get(requestid.Header),
+		Errors:    fields,
+	})
+}
+
+func writeLegacyError(
+	w http.ResponseWriter,
+	statusCode int,
+	message string,
+) {
+
+	w.Header().Add("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	if message != "" {
+		json.NewEncoder(w).Encode(
+			map[string]string{
+				"error": message,
+			},
+		)
+	}
+}
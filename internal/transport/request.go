@@ -0,0 +1,123 @@
+package transport
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/jackvonhouse/car-enrichment/internal/errors"
+	errpkg "github.com/jackvonhouse/car-enrichment/pkg/errors"
+	"github.com/jackvonhouse/car-enrichment/pkg/validate"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultLimit = 10
+	// maxLimit не даёт одним запросом выбрать всю таблицу
+	maxLimit = 100
+)
+
+// DecodeJSON читает тело запроса в dst. Неизвестные поля считаются
+// ошибкой, чтобы опечатка в имени поля не терялась молча
+func DecodeJSON(
+	r *http.Request,
+	dst any,
+) error {
+
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(dst); err != nil {
+		var (
+			syntaxErr *json.SyntaxError
+			typeErr   *json.UnmarshalTypeError
+		)
+
+		switch {
+		case errpkg.Is(err, io.EOF):
+			return errors.ErrInvalid.New("empty request body")
+
+		case errpkg.As(err, &syntaxErr):
+			return errors.ErrInvalid.New(
+				fmt.Sprintf("invalid json at position %d", syntaxErr.Offset),
+			).Wrap(err)
+
+		case errpkg.As(err, &typeErr):
+			return errors.ErrInvalid.New(
+				fmt.Sprintf("invalid json: field %s must be %s", typeErr.Field, typeErr.Type),
+			).Wrap(err)
+		}
+
+		// Сообщения о неизвестных полях и обрыве тела понятны клиенту как есть
+		return errors.ErrInvalid.New(
+			"invalid json: " + strings.TrimPrefix(err.Error(), "json: "),
+		).Wrap(err)
+	}
+
+	if decoder.More() {
+		return errors.ErrInvalid.New("invalid json: unexpected data after object")
+	}
+
+	return nil
+}
+
+// ParsePagination читает limit и offset. Без параметров используются
+// значения по умолчанию, некорректные значения попадают в v
+func ParsePagination(
+	queries url.Values,
+	v *validate.Validator,
+) (int, int) {
+
+	limit := ParseInt(queries, "limit", defaultLimit, v)
+	validate.Field(v, "limit", limit, validate.Range(1, maxLimit))
+
+	offset := ParseInt(queries, "offset", 0, v)
+	validate.Field(v, "offset", offset, validate.Min(0))
+
+	return limit, offset
+}
+
+// ParseInt читает целое число, без параметра возвращает fallback
+func ParseInt(
+	queries url.Values,
+	name string,
+	fallback int,
+	v *validate.Validator,
+) int {
+
+	value := queries.Get(name)
+	if value == "" {
+		return fallback
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		v.Add(name, "must be an integer")
+
+		return fallback
+	}
+
+	return number
+}
+
+// ParseBool читает логическое значение, без параметра возвращает false
+func ParseBool(
+	queries url.Values,
+	name string,
+	v *validate.Validator,
+) bool {
+
+	value := queries.Get(name)
+	if value == "" {
+		return false
+	}
+
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		v.Add(name, "must be a boolean")
+	}
+
+	return parsed
+}
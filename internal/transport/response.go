@@ -27,14 +27,5 @@ func Error(
 	message string,
 ) {
 
-	w.Header().Add("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
-
-	if message != "" {
-		json.NewEncoder(w).Encode(
-			map[string]string{
-				"error": message,
-			},
-		)
-	}
+	writeProblem(w, statusCode, statusProblemType(statusCode), message, nil)
 }
@@ -0,0 +1,132 @@
+package router
+
+import (
+	"context"
+	"github.com/gorilla/mux"
+	"github.com/jackvonhouse/car-enrichment/internal/identity"
+	"github.com/jackvonhouse/car-enrichment/pkg/log"
+	"net/http"
+	"time"
+)
+
+// access — сведения о запросе, которые становятся известны внутри цепочки
+// обработчиков. Контекст дочерних запросов не виден снаружи,
+// поэтому Auth записывает клиента сюда
+type access struct {
+	client string
+}
+
+type accessKey struct{}
+
+func recordClient(
+	ctx context.Context,
+	client identity.Identity,
+) {
+
+	if a, ok := ctx.Value(accessKey{}).(*access); ok {
+		a.client = client.Name
+	}
+}
+
+// AccessLog пишет одну запись журнала на каждый запрос:
+// маршрут, статус, размер ответа, время обработки и клиента
+func AccessLog(
+	logger log.Logger,
+) mux.MiddlewareFunc {
+
+	logger = logger.WithField("unit", "access")
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			a := &access{client: identity.Anonymous}
+			ctx := context.WithValue(r.Context(), accessKey{}, a)
+
+			rw := &responseWriter{ResponseWriter: w}
+
+			// Оборванный ответ тоже попадает в журнал, после чего
+			// паника передаётся дальше серверу
+			defer func() {
+				value := recover()
+
+				fields := map[string]any{
+					"method":    r.Method,
+					"path":      r.URL.Path,
+					"route":     routeTemplate(r),
+					"status":    rw.Status(),
+					"bytes":     rw.bytes,
+					"latencyMs": time.Since(start).Milliseconds(),
+					"client":    a.client,
+					"remote":    r.RemoteAddr,
+					"userAgent": r.UserAgent(),
+				}
+
+				if value != nil {
+					fields["status"] = http.StatusInternalServerError
+					fields["aborted"] = true
+				}
+
+				logger.WithContext(ctx).WithFields(fields).Info("request")
+
+				if value != nil {
+					panic(value)
+				}
+			}()
+
+			next.ServeHTTP(rw, r.WithContext(ctx))
+		})
+	}
+}
+
+// responseWriter запоминает статус и размер ответа
+type responseWriter struct {
+	http.ResponseWriter
+
+	status int
+	bytes  int
+}
+
+func (w *responseWriter) WriteHeader(
+	status int,
+) {
+
+	if w.status == 0 {
+		w.status = status
+	}
+
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseWriter) Write(
+	b []byte,
+) (int, error) {
+
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += n
+
+	return n, err
+}
+
+func (w *responseWriter) Status() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+
+	return w.status
+}
+
+// Flush нужен потоковой выдаче NDJSON
+func (w *responseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,146 @@
+package router
+
+import (
+	"context"
+	"github.com/gorilla/mux"
+	"github.com/jackvonhouse/car-enrichment/internal/errors"
+	"github.com/jackvonhouse/car-enrichment/internal/identity"
+	"github.com/jackvonhouse/car-enrichment/internal/transport"
+	errpkg "github.com/jackvonhouse/car-enrichment/pkg/errors"
+	"github.com/jackvonhouse/car-enrichment/pkg/log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const APIKeyHeader = "X-API-Key"
+
+type authenticator interface {
+	Authenticate(context.Context, string) (identity.Identity, error)
+}
+
+// Auth проверяет ключ API или токен пользователя и кладёт клиента
+// в контекст запроса. Токен передаётся в "Authorization: Bearer",
+// ключ — в заголовке X-API-Key или паролем Basic-авторизации,
+// чтобы swagger открывался в браузере.
+// Права Auth не проверяет: каждый маршрут объявляет их сам
+// через Scoped или RequireScope.
+// При выключенной проверке клиент берётся из X-Actor и получает все права
+func Auth(
+	enabled bool,
+	keys authenticator,
+	tokens authenticator,
+	logger log.Logger,
+) mux.MiddlewareFunc {
+
+	if !enabled {
+		return Actor
+	}
+
+	logger = logger.WithField("unit", "auth")
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			auth, credentials := credentials(r, keys, tokens)
+
+			if credentials == "" {
+				fail(w, errors.ErrUnauthorized.New("api key or bearer token required"))
+
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+			client, err := auth.Authenticate(ctx, credentials)
+			cancel()
+
+			if err != nil {
+				logger.WithContext(r.Context()).Warnf("[%s] %s: %s", r.Method, r.URL.Path, err)
+
+				fail(w, err)
+
+				return
+			}
+
+			client.Client = "client:" + client.Name
+
+			recordClient(r.Context(), client)
+
+			next.ServeHTTP(w, r.WithContext(identity.With(r.Context(), client)))
+		})
+	}
+}
+
+// credentials выбирает способ проверки по переданным заголовкам
+func credentials(
+	r *http.Request,
+	keys authenticator,
+	tokens authenticator,
+) (authenticator, string) {
+
+	scheme, value, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+	if strings.EqualFold(scheme, "Bearer") {
+		return tokens, strings.TrimSpace(value)
+	}
+
+	if key := strings.TrimSpace(r.Header.Get(APIKeyHeader)); key != "" {
+		return keys, key
+	}
+
+	if _, password, ok := r.BasicAuth(); ok {
+		return keys, password
+	}
+
+	return keys, ""
+}
+
+// RequireScope пропускает только клиентов с правом scope
+func RequireScope(
+	scope identity.Scope,
+) mux.MiddlewareFunc {
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			client := identity.FromContext(r.Context())
+
+			if !client.Has(scope) {
+				forbidden(w, scope)
+
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Scoped оборачивает обработчик маршрута проверкой права,
+// чтобы права объявлялись рядом с маршрутом
+func Scoped(
+	scope identity.Scope,
+	handler http.HandlerFunc,
+) http.Handler {
+
+	return RequireScope(scope)(handler)
+}
+
+// fail отвечает 401 или 403 в общем формате ошибок
+func fail(
+	w http.ResponseWriter,
+	err error,
+) {
+
+	if errpkg.TypeIs(err, errors.ErrUnauthorized) {
+		w.Header().Add("WWW-Authenticate", `Bearer realm="car-enrichment"`)
+		w.Header().Add("WWW-Authenticate", `Basic realm="car-enrichment"`)
+	}
+
+	transport.ErrorFrom(w, err, transport.DefaultErrorHttpCodes)
+}
+
+func forbidden(
+	w http.ResponseWriter,
+	scope identity.Scope,
+) {
+
+	fail(w, errors.ErrForbidden.New("scope "+string(scope)+" required"))
+}
@@ -0,0 +1,76 @@
+package router
+
+import (
+	"context"
+	"github.com/gorilla/mux"
+	"github.com/jackvonhouse/car-enrichment/internal/errors"
+	"github.com/jackvonhouse/car-enrichment/internal/identity"
+	"github.com/jackvonhouse/car-enrichment/pkg/log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type keys map[string]identity.Identity
+
+func (k keys) Authenticate(
+	_ context.Context,
+	key string,
+) (identity.Identity, error) {
+
+	client, ok := k[key]
+	if !ok {
+		return identity.Identity{}, errors.ErrUnauthorized.New("invalid api key")
+	}
+
+	return client, nil
+}
+
+func TestAuthScopes(t *testing.T) {
+	clients := keys{
+		"reader": {Name: "reader", Scopes: []identity.Scope{identity.ScopeRead}},
+		"writer": {Name: "writer", Scopes: []identity.Scope{identity.ScopeRead, identity.ScopeWrite}},
+		"admin":  {Name: "admin", Scopes: []identity.Scope{identity.ScopeAdmin}},
+	}
+
+	ok := func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNoContent) }
+
+	r := mux.NewRouter()
+	r.Use(Auth(true, clients, clients, log.NewLogrusLogger()))
+
+	r.Handle("/car", Scoped(identity.ScopeRead, ok)).Methods(http.MethodGet)
+	r.Handle("/car", Scoped(identity.ScopeWrite, ok)).Methods(http.MethodPost)
+	r.Handle("/car/{id}", Scoped(identity.ScopeDelete, ok)).Methods(http.MethodDelete)
+	// Права маршрута не обязаны совпадать с методом
+	r.Handle("/car/search", Scoped(identity.ScopeRead, ok)).Methods(http.MethodPost)
+
+	tests := []struct {
+		key    string
+		method string
+		path   string
+		status int
+	}{
+		{"", http.MethodGet, "/car", http.StatusUnauthorized},
+		{"unknown", http.MethodGet, "/car", http.StatusUnauthorized},
+		{"reader", http.MethodGet, "/car", http.StatusNoContent},
+		{"reader", http.MethodPost, "/car", http.StatusForbidden},
+		{"reader", http.MethodPost, "/car/search", http.StatusNoContent},
+		{"writer", http.MethodPost, "/car", http.StatusNoContent},
+		{"writer", http.MethodDelete, "/car/1", http.StatusForbidden},
+		{"admin", http.MethodDelete, "/car/1", http.StatusNoContent},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		if tt.key != "" {
+			req.Header.Set(APIKeyHeader, tt.key)
+		}
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != tt.status {
+			t.Errorf("%s %s %s: expected %d, got %d", tt.key, tt.method, tt.path, tt.status, w.Code)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package router
+
+import (
+	"github.com/jackvonhouse/car-enrichment/internal/identity"
+	"net/http"
+	"strings"
+)
+
+const ActorHeader = "X-Actor"
+
+// Actor подписывает изменения именем из X-Actor, когда ключи API
+// не проверяются, поэтому клиент получает все права
+func Actor(
+	next http.Handler,
+) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		client := identity.Identity{
+			Name:   strings.TrimSpace(r.Header.Get(ActorHeader)),
+			Scopes: identity.Scopes,
+			Client: ipKey(r),
+		}
+
+		ctx := identity.With(r.Context(), client)
+		recordClient(ctx, identity.FromContext(ctx))
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -0,0 +1,49 @@
+package router
+
+import (
+	"github.com/gorilla/mux"
+	"net/http"
+	"time"
+)
+
+type requestMetrics interface {
+	ObserveRequest(string, string, int, time.Duration)
+}
+
+// Metrics учитывает время обработки запроса по шаблону маршрута
+// и статусу. Шаблон вместо пути не даёт идентификаторам
+// из пути плодить ряды метрик
+func Metrics(
+	metrics requestMetrics,
+) mux.MiddlewareFunc {
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			rw, ok := w.(*responseWriter)
+			if !ok {
+				rw = &responseWriter{ResponseWriter: w}
+			}
+
+			// Оборванный ответ учитывается как 500: начатый статус
+			// клиент полностью не получил
+			defer func() {
+				value := recover()
+
+				status := rw.Status()
+				if value != nil {
+					status = http.StatusInternalServerError
+				}
+
+				metrics.ObserveRequest(r.Method, routeTemplate(r), status, time.Since(start))
+
+				if value != nil {
+					panic(value)
+				}
+			}()
+
+			next.ServeHTTP(rw, r)
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package router
+
+import (
+	"fmt"
+	"github.com/gorilla/mux"
+	"github.com/jackvonhouse/car-enrichment/config"
+	"github.com/jackvonhouse/car-enrichment/internal/errors"
+	"github.com/jackvonhouse/car-enrichment/internal/identity"
+	"github.com/jackvonhouse/car-enrichment/pkg/ratelimit"
+	"math"
+	"net"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// routePattern убирает регулярные выражения из шаблона маршрута:
+// "/car/{id:[0-9]+}" становится "/car/{id}"
+var routePattern = regexp.MustCompile(`\{([^:}]+):[^}]*\}`)
+
+// RateLimit ограничивает частоту запросов клиента к маршруту.
+// Маршрут определяется методом и шаблоном пути, у каждой пары клиента
+// и маршрута свой запас. Клиент определяется так же, как для квоты,
+// см. identity.Identity.Client
+func RateLimit(
+	config config.RateLimit,
+) mux.MiddlewareFunc {
+
+	if !config.Enabled {
+		return func(next http.Handler) http.Handler { return next }
+	}
+
+	routes := make(map[string]*ratelimit.Limiter, len(config.Routes))
+	policies := make(map[string]ratelimit.Limit, len(config.Routes))
+
+	for _, route := range config.Routes {
+		if !route.Limit.Valid() {
+			continue
+		}
+
+		name := strings.ToUpper(route.Method) + " " + route.Path
+
+		routes[name] = ratelimit.New(route.Limit)
+		policies[name] = route.Limit
+	}
+
+	var fallback *ratelimit.Limiter
+	if config.Default.Valid() {
+		fallback = ratelimit.New(config.Default)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			name := r.Method + " " + routeTemplate(r)
+			client := clientKey(r)
+
+			limiter, ok := routes[name]
+			policy := policies[name]
+
+			if !ok {
+				if fallback == nil {
+					next.ServeHTTP(w, r)
+
+					return
+				}
+
+				// Маршруты без своего лимита делят лимит по умолчанию,
+				// но запас у каждого маршрута свой
+				limiter, policy = fallback, config.Default
+				client = name + " " + client
+			}
+
+			result := limiter.Allow(client)
+
+			w.Header().Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d", policy.Requests, seconds(policy.Period)))
+			w.Header().Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+			w.Header().Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+			w.Header().Set("RateLimit-Reset", strconv.Itoa(seconds(result.Reset)))
+
+			if !result.Allowed {
+				w.Header().Set("Retry-After", strconv.Itoa(max(1, seconds(result.RetryAfter))))
+
+				fail(w, errors.ErrLimitExceeded.New("rate limit exceeded"))
+
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// AuthFailures ограничивает неудачные проверки подлинности с одного
+// IP-адреса, чтобы ключи и токены нельзя было подбирать. Стоит перед Auth:
+// каждая попытка берётся из запаса адреса заранее, удачная возвращается,
+// поэтому одновременные попытки тоже не обходят лимит
+func AuthFailures(
+	config config.RateLimit,
+) mux.MiddlewareFunc {
+
+	if !config.Enabled || !config.AuthFailures.Valid() {
+		return func(next http.Handler) http.Handler { return next }
+	}
+
+	limiter := ratelimit.New(config.AuthFailures)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			client := ipKey(r)
+
+			result := limiter.Allow(client)
+			if !result.Allowed {
+				w.Header().Set("Retry-After", strconv.Itoa(max(1, seconds(result.RetryAfter))))
+
+				fail(w, errors.ErrLimitExceeded.New("too many failed authentication attempts"))
+
+				return
+			}
+
+			rw, ok := w.(*responseWriter)
+			if !ok {
+				rw = &responseWriter{ResponseWriter: w}
+			}
+
+			defer func() {
+				if rw.Status() != http.StatusUnauthorized {
+					limiter.Refund(client)
+				}
+			}()
+
+			next.ServeHTTP(rw, r)
+		})
+	}
+}
+
+func routeTemplate(
+	r *http.Request,
+) string {
+
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return r.URL.Path
+	}
+
+	template, err := route.GetPathTemplate()
+	if err != nil {
+		return r.URL.Path
+	}
+
+	return routePattern.ReplaceAllString(template, "{$1}")
+}
+
+func clientKey(
+	r *http.Request,
+) string {
+
+	if client := identity.FromContext(r.Context()).Client; client != "" {
+		return client
+	}
+
+	return ipKey(r)
+}
+
+func ipKey(
+	r *http.Request,
+) string {
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	return "ip:" + host
+}
+
+func seconds(
+	d time.Duration,
+) int {
+
+	return int(math.Ceil(d.Seconds()))
+}
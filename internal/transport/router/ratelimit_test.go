@@ -0,0 +1,69 @@
+package router
+
+import (
+	"github.com/gorilla/mux"
+	"github.com/jackvonhouse/car-enrichment/config"
+	"github.com/jackvonhouse/car-enrichment/internal/identity"
+	"github.com/jackvonhouse/car-enrichment/pkg/log"
+	"github.com/jackvonhouse/car-enrichment/pkg/ratelimit"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAuthFailures(t *testing.T) {
+	clients := keys{
+		"reader": {Name: "reader", Scopes: []identity.Scope{identity.ScopeRead}},
+	}
+
+	r := mux.NewRouter()
+	r.Use(
+		AuthFailures(config.RateLimit{
+			Enabled:      true,
+			AuthFailures: ratelimit.Limit{Requests: 3, Period: time.Hour},
+		}),
+		Auth(true, clients, clients, log.NewLogrusLogger()),
+	)
+
+	r.Handle("/car", Scoped(identity.ScopeRead, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	request := func(key string, remote string) int {
+		req := httptest.NewRequest(http.MethodGet, "/car", nil)
+		req.RemoteAddr = remote
+		req.Header.Set(APIKeyHeader, key)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		return w.Code
+	}
+
+	// Удачные попытки не расходуют запас
+	for i := 0; i < 5; i++ {
+		if code := request("reader", "10.0.0.1:1000"); code != http.StatusNoContent {
+			t.Fatalf("valid key: expected %d, got %d", http.StatusNoContent, code)
+		}
+	}
+
+	for i := 0; i < 3; i++ {
+		if code := request("guess", "10.0.0.1:1000"); code != http.StatusUnauthorized {
+			t.Fatalf("attempt %d: expected %d, got %d", i+1, http.StatusUnauthorized, code)
+		}
+	}
+
+	if code := request("guess", "10.0.0.1:1001"); code != http.StatusTooManyRequests {
+		t.Errorf("over limit: expected %d, got %d", http.StatusTooManyRequests, code)
+	}
+
+	// Верный ключ после подбора тоже отклоняется, иначе ответ выдал бы его
+	if code := request("reader", "10.0.0.1:1002"); code != http.StatusTooManyRequests {
+		t.Errorf("valid key over limit: expected %d, got %d", http.StatusTooManyRequests, code)
+	}
+
+	if code := request("reader", "10.0.0.2:1000"); code != http.StatusNoContent {
+		t.Errorf("other address: expected %d, got %d", http.StatusNoContent, code)
+	}
+}
@@ -0,0 +1,57 @@
+package router
+
+import (
+	"github.com/gorilla/mux"
+	"github.com/jackvonhouse/car-enrichment/internal/transport"
+	"github.com/jackvonhouse/car-enrichment/pkg/log"
+	"net/http"
+	"runtime/debug"
+)
+
+// Recover перехватывает панику в обработчике, пишет стек в журнал
+// и отвечает 500 в общем формате ошибок. Если ответ уже начат,
+// статус изменить нельзя, и соединение закрывается без ответа,
+// а AccessLog и Metrics учитывают такой запрос со статусом 500
+func Recover(
+	logger log.Logger,
+) mux.MiddlewareFunc {
+
+	logger = logger.WithField("unit", "recover")
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rw, ok := w.(*responseWriter)
+			if !ok {
+				rw = &responseWriter{ResponseWriter: w}
+			}
+
+			defer func() {
+				value := recover()
+				if value == nil {
+					return
+				}
+
+				// http.ErrAbortHandler обрывает ответ намеренно
+				if value == http.ErrAbortHandler {
+					panic(value)
+				}
+
+				logger.WithContext(r.Context()).
+					WithField("stack", string(debug.Stack())).
+					Errorf("[%s] %s: panic: %v", r.Method, r.URL.Path, value)
+
+				if rw.status != 0 {
+					panic(http.ErrAbortHandler)
+				}
+
+				transport.Error(
+					rw,
+					http.StatusInternalServerError,
+					http.StatusText(http.StatusInternalServerError),
+				)
+			}()
+
+			next.ServeHTTP(rw, r)
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package router
+
+import (
+	"context"
+	"github.com/gorilla/mux"
+	"github.com/jackvonhouse/car-enrichment/pkg/log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// entries запоминает поля записей журнала уровня Info
+type entries struct {
+	log.Logger
+
+	fields  map[string]any
+	records *[]map[string]any
+}
+
+func newEntries() *entries {
+	return &entries{
+		Logger:  log.NewLogrusLogger(),
+		records: &[]map[string]any{},
+	}
+}
+
+func (e *entries) WithField(key string, value any) log.Logger {
+	return e.WithFields(map[string]any{key: value})
+}
+
+func (e *entries) WithFields(fields map[string]any) log.Logger {
+	merged := make(map[string]any, len(e.fields)+len(fields))
+
+	for k, v := range e.fields {
+		merged[k] = v
+	}
+
+	for k, v := range fields {
+		merged[k] = v
+	}
+
+	return &entries{Logger: e.Logger, fields: merged, records: e.records}
+}
+
+func (e *entries) WithContext(context.Context) log.Logger {
+	return e
+}
+
+func (e *entries) Info(...any) {
+	*e.records = append(*e.records, e.fields)
+}
+
+type observations []int
+
+func (o *observations) ObserveRequest(_ string, _ string, status int, _ time.Duration) {
+	*o = append(*o, status)
+}
+
+func TestRecoverAfterWriteIsRecorded(t *testing.T) {
+	logger := newEntries()
+	metrics := &observations{}
+
+	r := mux.NewRouter()
+	r.Use(
+		AccessLog(logger),
+		Metrics(metrics),
+		Recover(log.NewLogrusLogger()),
+	)
+
+	r.HandleFunc("/car", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("{}\n"))
+
+		panic("broken")
+	})
+
+	func() {
+		defer func() {
+			if value := recover(); value != http.ErrAbortHandler {
+				t.Errorf("expected http.ErrAbortHandler, got %v", value)
+			}
+		}()
+
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/car", nil))
+	}()
+
+	if len(*metrics) != 1 || (*metrics)[0] != http.StatusInternalServerError {
+		t.Errorf("metrics: expected one 500, got %v", *metrics)
+	}
+
+	if len(*logger.records) != 1 {
+		t.Fatalf("access log: expected one entry, got %d", len(*logger.records))
+	}
+
+	entry := (*logger.records)[0]
+	if entry["status"] != http.StatusInternalServerError || entry["aborted"] != true {
+		t.Errorf("access log: expected aborted 500, got %v", entry)
+	}
+}
+
+func TestRecoverBeforeWrite(t *testing.T) {
+	logger := newEntries()
+	metrics := &observations{}
+
+	r := mux.NewRouter()
+	r.Use(
+		AccessLog(logger),
+		Metrics(metrics),
+		Recover(log.NewLogrusLogger()),
+	)
+
+	r.HandleFunc("/car", func(w http.ResponseWriter, r *http.Request) {
+		panic("broken")
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/car", nil))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected %d, got %d", http.StatusInternalServerError, w.Code)
+	}
+
+	if len(*logger.records) != 1 {
+		t.Fatalf("access log: expected one entry, got %d", len(*logger.records))
+	}
+
+	if entry := (*logger.records)[0]; entry["status"] != http.StatusInternalServerError || entry["aborted"] != nil {
+		t.Errorf("access log: expected 500 without abort, got %v", entry)
+	}
+}
@@ -0,0 +1,63 @@
+package router
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"github.com/jackvonhouse/car-enrichment/internal/requestid"
+	"github.com/jackvonhouse/car-enrichment/pkg/log"
+	"net/http"
+	"strings"
+)
+
+const requestIdMaxLength = 128
+
+// RequestID принимает идентификатор запроса из X-Request-ID или создаёт новый.
+// Идентификатор возвращается в ответе и попадает в каждую запись журнала,
+// сделанную с контекстом запроса
+func RequestID(
+	next http.Handler,
+) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimSpace(r.Header.Get(requestid.Header))
+		if !validRequestId(id) {
+			id = newRequestId()
+		}
+
+		w.Header().Set(requestid.Header, id)
+
+		ctx := requestid.With(r.Context(), id)
+		ctx = log.ContextWithField(ctx, "requestId", id)
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// validRequestId не пускает в журнал длинные значения и управляющие символы
+func validRequestId(
+	id string,
+) bool {
+
+	if id == "" || len(id) > requestIdMaxLength {
+		return false
+	}
+
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case strings.ContainsRune("-_.:/+=", c):
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+func newRequestId() string {
+	b := make([]byte, 16)
+
+	// crypto/rand не возвращает ошибку на поддерживаемых системах
+	_, _ = rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
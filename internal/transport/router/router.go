@@ -2,9 +2,11 @@ package router
 
 import (
 	"github.com/gorilla/mux"
+	"net/http"
 )
 
 type Router struct {
+	root   *mux.Router
 	router *mux.Router
 }
 
@@ -16,12 +18,15 @@ func New(
 	pathPrefix string,
 ) *Router {
 
-	r := mux.NewRouter().
-		StrictSlash(false).
+	root := mux.NewRouter().
+		StrictSlash(false)
+
+	r := root.
 		PathPrefix(pathPrefix).
 		Subrouter()
 
 	return &Router{
+		root:   root,
 		router: r,
 	}
 }
@@ -36,4 +41,52 @@ func (r *Router) Handle(
 	}
 }
 
+func (r *Router) Use(
+	middlewares ...mux.MiddlewareFunc,
+) {
+
+	r.router.Use(middlewares...)
+}
+
+// UseRoot добавляет промежуточные обработчики ко всем запросам, включая
+// маршруты без префикса и ответы 404 и 405. Для них gorilla/mux
+// не вызывает промежуточные обработчики, поэтому они оборачиваются отдельно
+func (r *Router) UseRoot(
+	middlewares ...mux.MiddlewareFunc,
+) {
+
+	r.root.Use(middlewares...)
+
+	notFound := r.root.NotFoundHandler
+	if notFound == nil {
+		notFound = http.NotFoundHandler()
+	}
+
+	methodNotAllowed := r.root.MethodNotAllowedHandler
+	if methodNotAllowed == nil {
+		methodNotAllowed = http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		})
+	}
+
+	r.root.NotFoundHandler = chain(notFound, middlewares)
+	r.root.MethodNotAllowedHandler = chain(methodNotAllowed, middlewares)
+}
+
+func chain(
+	handler http.Handler,
+	middlewares []mux.MiddlewareFunc,
+) http.Handler {
+
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+
+	return handler
+}
+
 func (r *Router) Router() *mux.Router { return r.router }
+
+// Root — маршрутизатор без префикса, на нём действуют только
+// промежуточные обработчики из UseRoot
+func (r *Router) Root() *mux.Router { return r.root }
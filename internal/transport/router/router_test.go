@@ -0,0 +1,70 @@
+package router
+
+import (
+	"github.com/gorilla/mux"
+	"github.com/jackvonhouse/car-enrichment/internal/requestid"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type routes struct{}
+
+func (routes) Handle(r *mux.Router) {
+	r.HandleFunc("/{id:[0-9]+}", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}).Methods(http.MethodGet)
+}
+
+// Идентификатор запроса и запись журнала есть у любого ответа,
+// а не только у маршрутов под префиксом
+func TestUseRootCoversAllResponses(t *testing.T) {
+	logger := newEntries()
+
+	r := New("/api/v1")
+	r.UseRoot(RequestID, AccessLog(logger))
+
+	r.Handle(map[string]Handlify{"/car": routes{}})
+
+	r.Root().HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		method string
+		path   string
+		status int
+		route  string
+	}{
+		{method: http.MethodGet, path: "/api/v1/car/1", status: http.StatusNoContent, route: "/api/v1/car/{id}"},
+		{method: http.MethodGet, path: "/healthz", status: http.StatusOK, route: "/healthz"},
+		{method: http.MethodGet, path: "/api/v1/unknown", status: http.StatusNotFound, route: "/api/v1/unknown"},
+		{method: http.MethodDelete, path: "/api/v1/car/1", status: http.StatusMethodNotAllowed, route: "/api/v1/car/1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			*logger.records = nil
+
+			w := httptest.NewRecorder()
+			r.Root().ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if w.Code != tt.status {
+				t.Errorf("expected %d, got %d", tt.status, w.Code)
+			}
+
+			if w.Header().Get(requestid.Header) == "" {
+				t.Errorf("expected %s header", requestid.Header)
+			}
+
+			if len(*logger.records) != 1 {
+				t.Fatalf("access log: expected one entry, got %d", len(*logger.records))
+			}
+
+			entry := (*logger.records)[0]
+			if entry["status"] != tt.status || entry["route"] != tt.route {
+				t.Errorf("access log: expected %d %s, got %v %v", tt.status, tt.route, entry["status"], entry["route"])
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package search
+
+import (
+	"context"
+	"github.com/gorilla/mux"
+	"github.com/jackvonhouse/car-enrichment/internal/dto"
+	"github.com/jackvonhouse/car-enrichment/internal/identity"
+	"github.com/jackvonhouse/car-enrichment/internal/transport"
+	"github.com/jackvonhouse/car-enrichment/internal/transport/router"
+	"github.com/jackvonhouse/car-enrichment/pkg/log"
+	"github.com/jackvonhouse/car-enrichment/pkg/validate"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type carUseCase interface {
+	Search(context.Context, string, dto.Pagination) ([]dto.SearchResult, error)
+}
+
+// maxQueryLength ограничивает строку поиска, разбор длинных строк
+// в tsquery дорог, а пользы от них нет
+const maxQueryLength = 200
+
+type Transport struct {
+	car carUseCase
+
+	logger log.Logger
+}
+
+func New(
+	car carUseCase,
+	logger log.Logger,
+) Transport {
+	return Transport{
+		car:    car,
+		logger: logger.WithField("unit", "search"),
+	}
+}
+
+func (t Transport) Handle(
+	r *mux.Router,
+) {
+	r.Handle("", router.Scoped(identity.ScopeRead, t.Search)).
+		Methods(http.MethodGet)
+}
+
+// Search godoc
+// @Summary			Поиск автомобилей
+// @Description		Полнотекстовый поиск одновременно по гос. номеру, VIN, марке, модели и ФИО владельца.
+// @Description		Слова ищутся по префиксу, номер и VIN — также по подстроке.
+// @Description		Результаты упорядочены по релевантности, matched содержит совпавшие поля
+// @Accept			json
+// @Produce			json
+// @Param			q query string true "Строка поиска"
+// @Param			limit query int false "Лимит, от 1 до 100" default(10) minimum(1) maximum(100)
+// @Param			offset query int false "Смещение"
+// @Success			200 {array} dto.SearchResult
+// @Failure			422 {object} transport.Problem "Пустой запрос или некорректная пагинация"
+// @Failure			500 {object} transport.Problem "Неизвестная ошибка"
+// @Security		ApiKeyAuth
+// @Security		BearerAuth
+// @Tags			Поиск
+// @Router /search [get]
+func (t Transport) Search(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+
+	queries := r.URL.Query()
+
+	v := validate.New()
+
+	query := strings.TrimSpace(queries.Get("q"))
+	validate.Field(v, "q", query, validate.Required(), validate.Length(0, maxQueryLength))
+
+	limit, offset := transport.ParsePagination(queries, v)
+
+	if err := v.Err(); err != nil {
+		transport.ErrorFrom(w, err, transport.DefaultErrorHttpCodes)
+
+		return
+	}
+
+	pagination := dto.Pagination{
+		Limit:  limit,
+		Offset: offset,
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	results, err := t.car.Search(ctx, query, pagination)
+	if err != nil {
+		t.logger.WithContext(r.Context()).Warn(err)
+
+		transport.ErrorFrom(w, err, transport.DefaultErrorHttpCodes)
+
+		return
+	}
+
+	transport.Response(w, results)
+}
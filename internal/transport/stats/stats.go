@@ -0,0 +1,113 @@
+package stats
+
+import (
+	"context"
+	"github.com/gorilla/mux"
+	"github.com/jackvonhouse/car-enrichment/internal/dto"
+	"github.com/jackvonhouse/car-enrichment/internal/identity"
+	"github.com/jackvonhouse/car-enrichment/internal/transport"
+	"github.com/jackvonhouse/car-enrichment/internal/transport/car"
+	"github.com/jackvonhouse/car-enrichment/internal/transport/router"
+	"github.com/jackvonhouse/car-enrichment/pkg/log"
+	"github.com/jackvonhouse/car-enrichment/pkg/validate"
+	"net/http"
+	"time"
+)
+
+type carUseCase interface {
+	Stats(context.Context, dto.Filter, dto.StatsOptions) (dto.CarStats, error)
+}
+
+const (
+	defaultYearBucket = 5
+	maxYearBucket     = 50
+
+	defaultTop = 10
+	maxTop     = 100
+)
+
+type Transport struct {
+	car carUseCase
+
+	logger log.Logger
+}
+
+func New(
+	car carUseCase,
+	logger log.Logger,
+) Transport {
+	return Transport{
+		car:    car,
+		logger: logger.WithField("unit", "stats"),
+	}
+}
+
+func (t Transport) Handle(
+	r *mux.Router,
+) {
+	r.Handle("/cars", router.Scoped(identity.ScopeRead, t.Cars)).
+		Methods(http.MethodGet)
+}
+
+// Cars godoc
+// @Summary			Статистика автомобилей
+// @Description		Количество автомобилей и распределения по марке, модели, интервалам годов выпуска,
+// @Description		региону и владельцу. Принимает те же фильтры, что и получение автомобилей.
+// @Description		Неизвестные значения попадают в группу "unknown".
+// @Description		Результат кэшируется на короткое время
+// @Accept			json
+// @Produce			json
+// @Param			yearBucket query int false "Ширина интервала годов выпуска" default(5)
+// @Param			top query int false "Число групп по марке, модели, региону и владельцу" default(10)
+// @Param			regNum query string false "Гос. номер"
+// @Param			mark query string false "Марка"
+// @Param			model query string false "Модель"
+// @Param			year query int false "Год"
+// @Param			region query string false "Код региона, точное совпадение, операторы eq, ne, in"
+// @Param			ownerSurname query string false "Фамилия владельца"
+// @Success			200 {object} dto.CarStats
+// @Failure			422 {object} transport.Problem "Некорректная группировка или фильтр"
+// @Failure			500 {object} transport.Problem "Неизвестная ошибка"
+// @Security		ApiKeyAuth
+// @Security		BearerAuth
+// @Tags			Статистика
+// @Router /stats/cars [get]
+func (t Transport) Cars(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+
+	queries := r.URL.Query()
+
+	v := validate.New()
+
+	filter := car.ParseFilter(queries, v)
+
+	options := dto.StatsOptions{
+		YearBucket: transport.ParseInt(queries, "yearBucket", defaultYearBucket, v),
+		Top:        transport.ParseInt(queries, "top", defaultTop, v),
+	}
+
+	validate.Field(v, "yearBucket", options.YearBucket, validate.Range(1, maxYearBucket))
+	validate.Field(v, "top", options.Top, validate.Range(1, maxTop))
+
+	if err := v.Err(); err != nil {
+		transport.ErrorFrom(w, err, transport.DefaultErrorHttpCodes)
+
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	stats, err := t.car.Stats(ctx, filter, options)
+	if err != nil {
+		t.logger.WithContext(r.Context()).Warn(err)
+
+		transport.ErrorFrom(w, err, transport.DefaultErrorHttpCodes)
+
+		return
+	}
+
+	transport.Response(w, stats)
+}
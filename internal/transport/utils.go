@@ -1,8 +1,10 @@
 package transport
 
 import (
+	"mime"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/jackvonhouse/car-enrichment/internal/errors"
 	errpkg "github.com/jackvonhouse/car-enrichment/pkg/errors"
@@ -18,12 +20,38 @@ func StringToInt(valueStr string) (int, error) {
 	return valueInt, nil
 }
 
+// AcceptParam возвращает значение параметра медиатипа
+// из заголовка Accept, например version из "application/json; version=1"
+func AcceptParam(
+	r *http.Request,
+	name string,
+) string {
+
+	for _, header := range r.Header.Values("Accept") {
+		for _, value := range strings.Split(header, ",") {
+			_, params, err := mime.ParseMediaType(strings.TrimSpace(value))
+			if err != nil {
+				continue
+			}
+
+			if param, ok := params[name]; ok {
+				return param
+			}
+		}
+	}
+
+	return ""
+}
+
 var DefaultErrorHttpCodes = map[uint32]int{
 	errors.ErrInternal.TypeId:      http.StatusInternalServerError,
 	errors.ErrAlreadyExists.TypeId: http.StatusConflict,
 	errors.ErrNotFound.TypeId:      http.StatusNotFound,
 	errors.ErrInvalid.TypeId:       http.StatusBadRequest,
 	errors.ErrFailed.TypeId:        http.StatusBadRequest,
+	errors.ErrUnauthorized.TypeId:  http.StatusUnauthorized,
+	errors.ErrForbidden.TypeId:     http.StatusForbidden,
+	errors.ErrLimitExceeded.TypeId: http.StatusTooManyRequests,
 }
 
 func ErrorToHttpResponse(
@@ -44,3 +72,25 @@ func ErrorToHttpResponse(
 
 	return code, err.Error()
 }
+
+// Accepts сообщает, указан ли медиатип в заголовке Accept
+func Accepts(
+	r *http.Request,
+	mediaType string,
+) bool {
+
+	for _, header := range r.Header.Values("Accept") {
+		for _, value := range strings.Split(header, ",") {
+			parsed, _, err := mime.ParseMediaType(strings.TrimSpace(value))
+			if err != nil {
+				continue
+			}
+
+			if strings.EqualFold(parsed, mediaType) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
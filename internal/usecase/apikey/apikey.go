@@ -0,0 +1,66 @@
+package apikey
+
+import (
+	"context"
+	"github.com/jackvonhouse/car-enrichment/internal/dto"
+	"github.com/jackvonhouse/car-enrichment/internal/identity"
+	"github.com/jackvonhouse/car-enrichment/pkg/log"
+)
+
+type apiKeyService interface {
+	Issue(context.Context, dto.CreateAPIKey) (dto.IssuedAPIKey, error)
+
+	Get(context.Context) ([]dto.APIKey, error)
+
+	Revoke(context.Context, int64) error
+
+	Authenticate(context.Context, string) (identity.Identity, error)
+}
+
+type UseCase struct {
+	apiKey apiKeyService
+
+	logger log.Logger
+}
+
+func New(
+	apiKey apiKeyService,
+	logger log.Logger,
+) UseCase {
+
+	return UseCase{
+		apiKey: apiKey,
+		logger: logger.WithField("unit", "api key"),
+	}
+}
+
+func (u UseCase) Issue(
+	ctx context.Context,
+	create dto.CreateAPIKey,
+) (dto.IssuedAPIKey, error) {
+
+	return u.apiKey.Issue(ctx, create)
+}
+
+func (u UseCase) Get(
+	ctx context.Context,
+) ([]dto.APIKey, error) {
+
+	return u.apiKey.Get(ctx)
+}
+
+func (u UseCase) Revoke(
+	ctx context.Context,
+	keyId int64,
+) error {
+
+	return u.apiKey.Revoke(ctx, keyId)
+}
+
+func (u UseCase) Authenticate(
+	ctx context.Context,
+	key string,
+) (identity.Identity, error) {
+
+	return u.apiKey.Authenticate(ctx, key)
+}
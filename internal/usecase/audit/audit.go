@@ -0,0 +1,37 @@
+package audit
+
+import (
+	"context"
+	"github.com/jackvonhouse/car-enrichment/internal/dto"
+	"github.com/jackvonhouse/car-enrichment/pkg/log"
+)
+
+type auditService interface {
+	Get(context.Context, dto.AuditFilter, dto.Pagination) ([]dto.Audit, error)
+}
+
+type UseCase struct {
+	audit auditService
+
+	logger log.Logger
+}
+
+func New(
+	audit auditService,
+	logger log.Logger,
+) UseCase {
+
+	return UseCase{
+		audit:  audit,
+		logger: logger.WithField("unit", "audit"),
+	}
+}
+
+func (u UseCase) Get(
+	ctx context.Context,
+	filter dto.AuditFilter,
+	pagination dto.Pagination,
+) ([]dto.Audit, error) {
+
+	return u.audit.Get(ctx, filter, pagination)
+}
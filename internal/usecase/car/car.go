@@ -6,6 +6,7 @@ import (
 	"github.com/jackvonhouse/car-enrichment/internal/errors"
 	errpkg "github.com/jackvonhouse/car-enrichment/pkg/errors"
 	"github.com/jackvonhouse/car-enrichment/pkg/log"
+	"github.com/jackvonhouse/car-enrichment/pkg/safe"
 	"sync"
 )
 
@@ -13,14 +14,21 @@ type ownerService interface {
 	Create(context.Context, dto.CreateOwner) (int64, error)
 
 	GetByCarId(context.Context, int64) (dto.Owner, error)
+	GetByName(context.Context, dto.CreateOwner) (dto.Owner, error)
 }
 
 type carService interface {
 	Create(context.Context, map[int64]dto.Car) error
 
-	Get(context.Context, dto.Filter, dto.Pagination) ([]dto.Car, error)
+	Get(context.Context, dto.Filter, dto.Pagination) (dto.CarList, error)
 	GetById(context.Context, int64) (dto.Car, error)
 
+	Search(context.Context, string, dto.Pagination) ([]dto.SearchResult, error)
+
+	Stats(context.Context, dto.Filter, dto.StatsOptions) (dto.CarStats, error)
+
+	Iterate(context.Context, dto.Filter, []dto.Sort, func(dto.Car) error) error
+
 	Update(context.Context, dto.Car) error
 
 	Delete(context.Context, int64) error
@@ -30,11 +38,21 @@ type enrichmentService interface {
 	Enrichment(context.Context, []string) (map[int64]dto.Car, error)
 }
 
+type dictionaryService interface {
+	Normalize(context.Context, string, string) (dto.Normalized, error)
+}
+
+type quotaService interface {
+	ConsumeEnrichment(context.Context, int) error
+}
+
 type UseCase struct {
 	car   carService
 	owner ownerService
 
 	enrichment enrichmentService
+	dictionary dictionaryService
+	quota      quotaService
 
 	logger log.Logger
 }
@@ -43,6 +61,8 @@ func New(
 	car carService,
 	owner ownerService,
 	enrichment enrichmentService,
+	dictionary dictionaryService,
+	quota quotaService,
 	logger log.Logger,
 ) UseCase {
 
@@ -50,6 +70,8 @@ func New(
 		car:        car,
 		owner:      owner,
 		enrichment: enrichment,
+		dictionary: dictionary,
+		quota:      quota,
 		logger:     logger.WithField("unit", "car"),
 	}
 }
@@ -59,7 +81,13 @@ func (u UseCase) Create(
 	create dto.CreateCar,
 ) (map[int64]string, error) {
 
-	u.logger.Debug("starting enrichment")
+	// Квота списывается до запросов во внешний API,
+	// так как платными считаются и неудачные запросы
+	if err := u.quota.ConsumeEnrichment(ctx, len(create.RegNumbers)); err != nil {
+		return map[int64]string{}, err
+	}
+
+	u.logger.WithContext(ctx).Debug("starting enrichment")
 
 	enrichmentCars, err := u.enrichment.Enrichment(ctx, create.RegNumbers)
 	if err != nil {
@@ -67,41 +95,73 @@ func (u UseCase) Create(
 	}
 
 	if len(enrichmentCars) == 0 {
-		u.logger.Warnf("enrichment failed for cars")
+		u.logger.WithContext(ctx).Warnf("enrichment failed for cars")
 
 		return map[int64]string{}, errors.ErrInternal.New("enrichment failed for cars").Wrap(err)
 	}
 
-	u.logger.Debug("enrichment finished")
+	u.logger.WithContext(ctx).Debug("enrichment finished")
 
 	failedEnrichmentCars := map[int64]string{}
 
 	if len(enrichmentCars) != len(create.RegNumbers) {
 		failedEnrichmentCars = u.getFailedEnrichmentCars(create.RegNumbers, enrichmentCars)
-		u.logger.Debugf("failed enrichment cars: %d", len(failedEnrichmentCars))
+		u.logger.WithContext(ctx).Debugf("failed enrichment cars: %d", len(failedEnrichmentCars))
 
-		u.logger.Debugf(
+		u.logger.WithContext(ctx).Debugf(
 			"not all cars are enriched (%d), actual (%d)",
 			len(create.RegNumbers),
 			len(enrichmentCars),
 		)
 	}
 
-	u.logger.Debug("starting create owners")
+	u.logger.WithContext(ctx).Debug("starting create owners")
 
 	enrichmentCarsWithOwners, err := u.createOrGetOwners(ctx, enrichmentCars)
 	if err != nil {
 		return failedEnrichmentCars, err
 	}
 
-	u.logger.Debugf(
+	u.logger.WithContext(ctx).Debugf(
 		"enrichment cars after join owners: %d",
 		len(enrichmentCarsWithOwners),
 	)
 
+	if len(enrichmentCarsWithOwners) != len(enrichmentCars) {
+		failedEnrichmentCars = u.getFailedEnrichmentCars(create.RegNumbers, enrichmentCarsWithOwners)
+	}
+
+	if len(enrichmentCarsWithOwners) == 0 {
+		return failedEnrichmentCars, errors.ErrInternal.New("can't create owners for cars")
+	}
+
+	for id, car := range enrichmentCarsWithOwners {
+		enrichmentCarsWithOwners[id] = u.normalize(ctx, car)
+	}
+
 	return failedEnrichmentCars, u.car.Create(ctx, enrichmentCarsWithOwners)
 }
 
+// normalize приводит марку и модель из внешнего API к справочнику.
+// Ошибка справочника не мешает сохранить автомобиль с исходными значениями
+func (u UseCase) normalize(
+	ctx context.Context,
+	car dto.Car,
+) dto.Car {
+
+	normalized, err := u.dictionary.Normalize(ctx, car.Mark, car.Model)
+	if err != nil {
+		u.logger.WithContext(ctx).Warnf("can't normalize mark and model for %s: %s", car.RegNum, err)
+
+		return car
+	}
+
+	car.Mark, car.MarkID = normalized.Mark, normalized.MarkID
+	car.Model, car.ModelID = normalized.Model, normalized.ModelID
+
+	return car
+}
+
 func (u UseCase) getFailedEnrichmentCars(
 	regNumbers []string,
 	enrichmentCars map[int64]dto.Car,
@@ -127,7 +187,6 @@ func (u UseCase) createOrGetOwners(
 
 	mu := &sync.Mutex{}
 	wg := &sync.WaitGroup{}
-	wg.Add(len(enrichmentCars))
 
 	enrichmentCarsCopy := make(map[int64]dto.Car, len(enrichmentCars))
 
@@ -135,39 +194,26 @@ func (u UseCase) createOrGetOwners(
 		enrichmentCarsCopy[carId] = car
 	}
 
-	var ownerId int64
-
-	for carId, car := range enrichmentCarsCopy {
-		u.logger.Debug("starting join owner to car with id %d", carId)
-
-		go func(carId int64, car dto.Car) {
-			defer wg.Done()
-
-			owner, err := u.owner.GetByCarId(ctx, carId)
-
-			if err == nil {
-				u.logger.Infof("owner for car id (%d) already exists", carId)
-
-				ownerId = owner.ID
-			} else {
-				u.logger.Infof("can't get owner by car id (%d): %s", carId, err)
-				u.logger.Debugf("try to create owner for car id (%d)", carId)
-
-				ownerId, err = u.owner.Create(ctx, dto.CreateOwner{
-					Name:       car.Owner.Name,
-					Surname:    car.Owner.Surname,
-					Patronymic: car.Owner.Patronymic,
-				})
+	// drop убирает автомобиль, для которого не удалось получить владельца,
+	// и он попадает в список необработанных номеров
+	drop := func(carId int64) {
+		mu.Lock()
+		delete(enrichmentCarsCopy, carId)
+		mu.Unlock()
+	}
 
-				if err != nil && !errpkg.TypeIs(err, errors.ErrAlreadyExists) {
-					u.logger.Warnf("can't create owner for car id (%d): %s", carId, err)
+	for carId, car := range enrichmentCars {
+		u.logger.WithContext(ctx).Debugf("starting join owner to car with id %d", carId)
 
-					mu.Lock()
-					delete(enrichmentCarsCopy, carId)
-					mu.Unlock()
-				}
+		safe.Go(wg, func() error {
+			ownerId, err := u.createOrGetOwner(ctx, dto.CreateOwner{
+				Name:       car.Owner.Name,
+				Surname:    car.Owner.Surname,
+				Patronymic: car.Owner.Patronymic,
+			})
 
-				u.logger.Debugf("owner created for car id (%d)", carId)
+			if err != nil {
+				return err
 			}
 
 			mu.Lock()
@@ -176,9 +222,20 @@ func (u UseCase) createOrGetOwners(
 				enrichmentCarsCopy[carId] = c
 			}
 			mu.Unlock()
-		}(carId, car)
 
-		u.logger.Debugf("joined owner to car with id %d", carId)
+			return nil
+		}, func(err error) {
+			logger := u.logger.WithContext(ctx)
+			if stack := safe.Stack(err); stack != "" {
+				logger = logger.WithField("stack", stack)
+			}
+
+			logger.Warnf("can't create owner for car id (%d): %s", carId, err)
+
+			drop(carId)
+		})
+
+		u.logger.WithContext(ctx).Debugf("joined owner to car with id %d", carId)
 	}
 
 	wg.Wait()
@@ -186,28 +243,110 @@ func (u UseCase) createOrGetOwners(
 	return enrichmentCarsCopy, nil
 }
 
+// createOrGetOwner создаёт владельца, а если он уже есть,
+// находит его по полному имени
+func (u UseCase) createOrGetOwner(
+	ctx context.Context,
+	name dto.CreateOwner,
+) (int64, error) {
+
+	id, err := u.owner.Create(ctx, name)
+
+	if errpkg.TypeIs(err, errors.ErrAlreadyExists) {
+		owner, err := u.owner.GetByName(ctx, name)
+		if err != nil {
+			return 0, err
+		}
+
+		return owner.ID, nil
+	}
+
+	return id, err
+}
+
 func (u UseCase) Get(
 	ctx context.Context,
 	filter dto.Filter,
 	pagination dto.Pagination,
-) ([]dto.Car, error) {
+) (dto.CarList, error) {
 
 	return u.car.Get(ctx, filter, pagination)
 }
 
+func (u UseCase) Search(
+	ctx context.Context,
+	query string,
+	pagination dto.Pagination,
+) ([]dto.SearchResult, error) {
+
+	return u.car.Search(ctx, query, pagination)
+}
+
+func (u UseCase) Stats(
+	ctx context.Context,
+	filter dto.Filter,
+	options dto.StatsOptions,
+) (dto.CarStats, error) {
+
+	return u.car.Stats(ctx, filter, options)
+}
+
+func (u UseCase) Iterate(
+	ctx context.Context,
+	filter dto.Filter,
+	sort []dto.Sort,
+	fn func(dto.Car) error,
+) error {
+
+	return u.car.Iterate(ctx, filter, sort, fn)
+}
+
 func (u UseCase) Update(
 	ctx context.Context,
 	car dto.Car,
 ) error {
 
-	owner, err := u.owner.GetByCarId(ctx, car.ID)
-	if err != nil {
-		u.logger.Warnf("can't get owner by car id (%d): %s", car.ID, err)
+	// Без owner.id изменяется текущий владелец, другой owner.id
+	// означает передачу автомобиля
+	if car.Owner.ID == 0 {
+		owner, err := u.owner.GetByCarId(ctx, car.ID)
+		if err != nil {
+			u.logger.WithContext(ctx).Warnf("can't get owner by car id (%d): %s", car.ID, err)
+
+			return err
+		}
 
-		return err
+		car.Owner.ID = owner.ID
 	}
 
-	car.Owner.ID = owner.ID
+	// Модель сопоставляется в пределах марки, поэтому при изменении
+	// любого из полей заново сопоставляются оба
+	if car.Mark != "" || car.Model != "" {
+		current, err := u.car.GetById(ctx, car.ID)
+		if err != nil {
+			u.logger.WithContext(ctx).Warnf("can't get car by id (%d): %s", car.ID, err)
+
+			return err
+		}
+
+		if car.Mark == "" {
+			car.Mark = current.Mark
+		}
+
+		if car.Model == "" {
+			car.Model = current.Model
+		}
+
+		normalized, err := u.dictionary.Normalize(ctx, car.Mark, car.Model)
+		if err != nil {
+			u.logger.WithContext(ctx).Warnf("can't normalize mark and model for car id (%d): %s", car.ID, err)
+
+			return err
+		}
+
+		car.Mark, car.MarkID = normalized.Mark, normalized.MarkID
+		car.Model, car.ModelID = normalized.Model, normalized.ModelID
+	}
 
 	return u.car.Update(ctx, car)
 }
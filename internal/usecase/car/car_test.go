@@ -0,0 +1,142 @@
+package car
+
+import (
+	"context"
+	"github.com/jackvonhouse/car-enrichment/internal/dto"
+	"github.com/jackvonhouse/car-enrichment/internal/errors"
+	"github.com/jackvonhouse/car-enrichment/pkg/log"
+	"sync"
+	"testing"
+)
+
+// owners хранит владельцев по полному имени, как уникальный индекс в базе
+type owners struct {
+	mu  sync.Mutex
+	ids map[dto.CreateOwner]int64
+}
+
+func (o *owners) Create(_ context.Context, name dto.CreateOwner) (int64, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if _, ok := o.ids[name]; ok {
+		return 0, errors.ErrAlreadyExists.New("owner already exists")
+	}
+
+	o.ids[name] = int64(len(o.ids) + 1)
+
+	return o.ids[name], nil
+}
+
+// GetByCarId возвращает владельца 7 для любого автомобиля
+func (o *owners) GetByCarId(context.Context, int64) (dto.Owner, error) {
+	return dto.Owner{ID: 7}, nil
+}
+
+func (o *owners) GetByName(_ context.Context, name dto.CreateOwner) (dto.Owner, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	id, ok := o.ids[name]
+	if !ok {
+		return dto.Owner{}, errors.ErrNotFound.New("owner not found")
+	}
+
+	return dto.Owner{ID: id, Name: name.Name, Surname: name.Surname, Patronymic: name.Patronymic}, nil
+}
+
+type cars struct {
+	carService
+
+	created map[int64]dto.Car
+	updated dto.Car
+}
+
+func (c *cars) Create(_ context.Context, created map[int64]dto.Car) error {
+	c.created = created
+
+	return nil
+}
+
+func (c *cars) Update(_ context.Context, updated dto.Car) error {
+	c.updated = updated
+
+	return nil
+}
+
+type enrichment map[int64]dto.Car
+
+func (e enrichment) Enrichment(context.Context, []string) (map[int64]dto.Car, error) {
+	return e, nil
+}
+
+type dictionary struct{}
+
+func (dictionary) Normalize(_ context.Context, mark string, model string) (dto.Normalized, error) {
+	return dto.Normalized{Mark: mark, Model: model}, nil
+}
+
+type quota struct{}
+
+func (quota) ConsumeEnrichment(context.Context, int) error { return nil }
+
+func TestCreateCarsWithSharedOwner(t *testing.T) {
+	owner := dto.Owner{Name: "Иван", Surname: "Иванов", Patronymic: "Иванович"}
+
+	o := &owners{ids: map[dto.CreateOwner]int64{}}
+	c := &cars{}
+
+	u := New(c, o, enrichment{
+		0: {RegNum: "X123XX150", Owner: owner},
+		1: {RegNum: "Y456YY77", Owner: owner},
+	}, dictionary{}, quota{}, log.NewLogrusLogger())
+
+	failed, err := u.Create(context.Background(), dto.CreateCar{
+		RegNumbers: []string{"X123XX150", "Y456YY77"},
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(failed) != 0 {
+		t.Errorf("expected no failed cars, got %v", failed)
+	}
+
+	if len(c.created) != 2 {
+		t.Fatalf("expected 2 created cars, got %d", len(c.created))
+	}
+
+	for id, car := range c.created {
+		if car.Owner.ID != 1 {
+			t.Errorf("car %d: expected owner id 1, got %d", id, car.Owner.ID)
+		}
+	}
+}
+
+func TestUpdateOwner(t *testing.T) {
+	tests := []struct {
+		name    string
+		ownerId int64
+		want    int64
+	}{
+		{name: "current owner", ownerId: 0, want: 7},
+		{name: "transfer", ownerId: 3, want: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &cars{}
+			u := New(c, &owners{}, enrichment{}, dictionary{}, quota{}, log.NewLogrusLogger())
+
+			err := u.Update(context.Background(), dto.Car{ID: 1, Owner: dto.Owner{ID: tt.ownerId}})
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if c.updated.Owner.ID != tt.want {
+				t.Errorf("expected owner id %d, got %d", tt.want, c.updated.Owner.ID)
+			}
+		})
+	}
+}
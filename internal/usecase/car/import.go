@@ -0,0 +1,266 @@
+package car
+
+import (
+	"context"
+	"fmt"
+	"github.com/jackvonhouse/car-enrichment/internal/dto"
+)
+
+const (
+	// Номеров в одном запросе на обогащение
+	importEnrichBatch = 100
+	// Номеров в одной проверке на существование
+	importExistingBatch = 500
+)
+
+// Import загружает автомобили построчно: ошибка в одной строке
+// не мешает остальным и попадает в результат этой строки.
+// В режиме DryRun строки только проверяются, внешний API не вызывается
+func (u UseCase) Import(
+	ctx context.Context,
+	rows []dto.ImportRow,
+	options dto.ImportOptions,
+) (dto.ImportResult, error) {
+
+	result := dto.ImportResult{
+		DryRun: options.DryRun,
+		Total:  len(rows),
+		Rows:   make([]dto.ImportRowResult, len(rows)),
+	}
+
+	fail := func(i int, msg string) {
+		result.Rows[i].Status = dto.ImportStatusFailed
+		result.Rows[i].Error = msg
+	}
+
+	// pending — строки, прошедшие проверку, в порядке файла
+	pending := make([]int, 0, len(rows))
+
+	seenRegNums := make(map[string]int, len(rows))
+	seenVINs := make(map[string]int)
+
+	for i, row := range rows {
+		result.Rows[i] = dto.ImportRowResult{
+			Line:   row.Line,
+			RegNum: row.Car.RegNum,
+		}
+
+		if row.Error != "" {
+			fail(i, row.Error)
+
+			continue
+		}
+
+		if line, ok := seenRegNums[row.Car.RegNum]; ok {
+			fail(i, fmt.Sprintf("duplicate registration number, see line %d", line))
+
+			continue
+		}
+
+		seenRegNums[row.Car.RegNum] = row.Line
+
+		if row.Car.VIN != nil {
+			if line, ok := seenVINs[*row.Car.VIN]; ok {
+				fail(i, fmt.Sprintf("duplicate vin, see line %d", line))
+
+				continue
+			}
+
+			seenVINs[*row.Car.VIN] = row.Line
+		}
+
+		if row.PlateOnly && !options.Enrich {
+			fail(i, "only registration number given, enable enrich to import it")
+
+			continue
+		}
+
+		pending = append(pending, i)
+	}
+
+	existing, err := u.existing(ctx, rows, pending)
+	if err != nil {
+		return dto.ImportResult{}, err
+	}
+
+	cars := make(map[int]dto.Car, len(pending))
+	plateOnly := make([]int, 0)
+
+	for _, i := range pending {
+		if _, ok := existing[rows[i].Car.RegNum]; ok {
+			fail(i, "car already exists")
+
+			continue
+		}
+
+		if options.DryRun {
+			result.Rows[i].Status = dto.ImportStatusValid
+
+			continue
+		}
+
+		if rows[i].PlateOnly {
+			plateOnly = append(plateOnly, i)
+
+			continue
+		}
+
+		cars[i] = rows[i].Car
+	}
+
+	if !options.DryRun {
+		u.importEnrich(ctx, rows, plateOnly, cars, fail)
+
+		owners := make(map[dto.CreateOwner]int64)
+
+		for _, i := range pending {
+			car, ok := cars[i]
+			if !ok {
+				continue
+			}
+
+			if err := u.importCar(ctx, car, owners); err != nil {
+				u.logger.WithContext(ctx).Warnf("can't import line %d: %s", rows[i].Line, err)
+
+				fail(i, err.Error())
+
+				continue
+			}
+
+			result.Rows[i].Status = dto.ImportStatusCreated
+		}
+	}
+
+	for _, row := range result.Rows {
+		switch row.Status {
+
+		case dto.ImportStatusCreated:
+			result.Created++
+
+		case dto.ImportStatusValid:
+			result.Valid++
+
+		case dto.ImportStatusFailed:
+			result.Failed++
+		}
+	}
+
+	return result, nil
+}
+
+// existing возвращает уже сохранённые номера среди строк,
+// чтобы не обогащать и не вставлять их повторно
+func (u UseCase) existing(
+	ctx context.Context,
+	rows []dto.ImportRow,
+	indexes []int,
+) (map[string]struct{}, error) {
+
+	existing := make(map[string]struct{})
+
+	for start := 0; start < len(indexes); start += importExistingBatch {
+		end := min(start+importExistingBatch, len(indexes))
+
+		regNums := make([]string, 0, end-start)
+		for _, i := range indexes[start:end] {
+			regNums = append(regNums, rows[i].Car.RegNum)
+		}
+
+		filter := dto.Filter{
+			RegNum: []dto.Condition{{
+				Operator: dto.OperatorIn,
+				Values:   regNums,
+			}},
+		}
+
+		err := u.car.Iterate(ctx, filter, nil, func(car dto.Car) error {
+			existing[car.RegNum] = struct{}{}
+
+			return nil
+		})
+
+		if err != nil {
+			u.logger.WithContext(ctx).Warnf("can't check existing cars: %s", err)
+
+			return nil, err
+		}
+	}
+
+	return existing, nil
+}
+
+// importEnrich обогащает строки только с номером пачками,
+// чтобы не отправлять во внешний API тысячи запросов разом
+func (u UseCase) importEnrich(
+	ctx context.Context,
+	rows []dto.ImportRow,
+	indexes []int,
+	cars map[int]dto.Car,
+	fail func(int, string),
+) {
+
+	for start := 0; start < len(indexes); start += importEnrichBatch {
+		batch := indexes[start:min(start+importEnrichBatch, len(indexes))]
+
+		regNums := make([]string, len(batch))
+		for j, i := range batch {
+			regNums[j] = rows[i].Car.RegNum
+		}
+
+		if err := u.quota.ConsumeEnrichment(ctx, len(regNums)); err != nil {
+			u.logger.WithContext(ctx).Warnf("can't enrich imported cars: %s", err)
+
+			for _, i := range indexes[start:] {
+				fail(i, err.Error())
+			}
+
+			return
+		}
+
+		enriched, err := u.enrichment.Enrichment(ctx, regNums)
+		if err != nil {
+			u.logger.WithContext(ctx).Warnf("can't enrich imported cars: %s", err)
+		}
+
+		for j, i := range batch {
+			car, ok := enriched[int64(j)]
+			if !ok {
+				fail(i, "enrichment failed")
+
+				continue
+			}
+
+			cars[i] = car
+		}
+	}
+}
+
+// importCar сохраняет один автомобиль с владельцем. Владелец ищется
+// по полному имени, owners переиспользует найденных в пределах загрузки
+func (u UseCase) importCar(
+	ctx context.Context,
+	car dto.Car,
+	owners map[dto.CreateOwner]int64,
+) error {
+
+	name := dto.CreateOwner{
+		Name:       car.Owner.Name,
+		Surname:    car.Owner.Surname,
+		Patronymic: car.Owner.Patronymic,
+	}
+
+	ownerId, ok := owners[name]
+	if !ok {
+		id, err := u.createOrGetOwner(ctx, name)
+		if err != nil {
+			return err
+		}
+
+		ownerId = id
+		owners[name] = id
+	}
+
+	car.Owner.ID = ownerId
+
+	return u.car.Create(ctx, map[int64]dto.Car{0: u.normalize(ctx, car)})
+}
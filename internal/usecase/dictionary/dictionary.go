@@ -0,0 +1,124 @@
+package dictionary
+
+import (
+	"context"
+	"github.com/jackvonhouse/car-enrichment/internal/dto"
+	"github.com/jackvonhouse/car-enrichment/pkg/log"
+)
+
+type dictionaryService interface {
+	GetMarks(context.Context) ([]dto.Mark, error)
+	GetModels(context.Context, int64) ([]dto.Model, error)
+
+	CreateMark(context.Context, dto.CreateMark) (int64, error)
+	CreateModel(context.Context, dto.CreateModel) (int64, error)
+
+	AddMarkAlias(context.Context, int64, string) error
+	AddModelAlias(context.Context, int64, string) error
+
+	DeleteMark(context.Context, int64) error
+	DeleteModel(context.Context, int64) error
+
+	DeleteMarkAlias(context.Context, int64, string) error
+	DeleteModelAlias(context.Context, int64, string) error
+}
+
+type UseCase struct {
+	dictionary dictionaryService
+
+	logger log.Logger
+}
+
+func New(
+	dictionary dictionaryService,
+	logger log.Logger,
+) UseCase {
+
+	return UseCase{
+		dictionary: dictionary,
+		logger:     logger.WithField("unit", "dictionary"),
+	}
+}
+
+func (u UseCase) GetMarks(
+	ctx context.Context,
+) ([]dto.Mark, error) {
+
+	return u.dictionary.GetMarks(ctx)
+}
+
+func (u UseCase) GetModels(
+	ctx context.Context,
+	markId int64,
+) ([]dto.Model, error) {
+
+	return u.dictionary.GetModels(ctx, markId)
+}
+
+func (u UseCase) CreateMark(
+	ctx context.Context,
+	create dto.CreateMark,
+) (int64, error) {
+
+	return u.dictionary.CreateMark(ctx, create)
+}
+
+func (u UseCase) CreateModel(
+	ctx context.Context,
+	create dto.CreateModel,
+) (int64, error) {
+
+	return u.dictionary.CreateModel(ctx, create)
+}
+
+func (u UseCase) AddMarkAlias(
+	ctx context.Context,
+	markId int64,
+	alias string,
+) error {
+
+	return u.dictionary.AddMarkAlias(ctx, markId, alias)
+}
+
+func (u UseCase) AddModelAlias(
+	ctx context.Context,
+	modelId int64,
+	alias string,
+) error {
+
+	return u.dictionary.AddModelAlias(ctx, modelId, alias)
+}
+
+func (u UseCase) DeleteMark(
+	ctx context.Context,
+	markId int64,
+) error {
+
+	return u.dictionary.DeleteMark(ctx, markId)
+}
+
+func (u UseCase) DeleteModel(
+	ctx context.Context,
+	modelId int64,
+) error {
+
+	return u.dictionary.DeleteModel(ctx, modelId)
+}
+
+func (u UseCase) DeleteMarkAlias(
+	ctx context.Context,
+	markId int64,
+	alias string,
+) error {
+
+	return u.dictionary.DeleteMarkAlias(ctx, markId, alias)
+}
+
+func (u UseCase) DeleteModelAlias(
+	ctx context.Context,
+	modelId int64,
+	alias string,
+) error {
+
+	return u.dictionary.DeleteModelAlias(ctx, modelId, alias)
+}
@@ -0,0 +1,43 @@
+package health
+
+import (
+	"context"
+	"github.com/jackvonhouse/car-enrichment/internal/dto"
+	"github.com/jackvonhouse/car-enrichment/pkg/log"
+)
+
+type healthService interface {
+	Live(context.Context) dto.Health
+	Ready(context.Context) dto.Health
+}
+
+type UseCase struct {
+	health healthService
+
+	logger log.Logger
+}
+
+func New(
+	health healthService,
+	logger log.Logger,
+) UseCase {
+
+	return UseCase{
+		health: health,
+		logger: logger.WithField("unit", "health"),
+	}
+}
+
+func (u UseCase) Live(
+	ctx context.Context,
+) dto.Health {
+
+	return u.health.Live(ctx)
+}
+
+func (u UseCase) Ready(
+	ctx context.Context,
+) dto.Health {
+
+	return u.health.Ready(ctx)
+}
@@ -0,0 +1,36 @@
+package token
+
+import (
+	"context"
+	"github.com/jackvonhouse/car-enrichment/internal/identity"
+	"github.com/jackvonhouse/car-enrichment/pkg/log"
+)
+
+type tokenService interface {
+	Authenticate(context.Context, string) (identity.Identity, error)
+}
+
+type UseCase struct {
+	token tokenService
+
+	logger log.Logger
+}
+
+func New(
+	token tokenService,
+	logger log.Logger,
+) UseCase {
+
+	return UseCase{
+		token:  token,
+		logger: logger.WithField("unit", "token"),
+	}
+}
+
+func (u UseCase) Authenticate(
+	ctx context.Context,
+	token string,
+) (identity.Identity, error) {
+
+	return u.token.Authenticate(ctx, token)
+}
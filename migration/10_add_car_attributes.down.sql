@@ -0,0 +1,14 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_car_body_type CASCADE;
+DROP INDEX IF EXISTS idx_car_fuel_type CASCADE;
+
+ALTER TABLE car DROP COLUMN IF EXISTS color;
+ALTER TABLE car DROP COLUMN IF EXISTS body_type;
+ALTER TABLE car DROP COLUMN IF EXISTS fuel_type;
+ALTER TABLE car DROP COLUMN IF EXISTS engine_volume;
+ALTER TABLE car DROP COLUMN IF EXISTS power;
+ALTER TABLE car DROP COLUMN IF EXISTS mileage;
+ALTER TABLE car DROP COLUMN IF EXISTS mileage_at;
+
+COMMIT;
@@ -0,0 +1,27 @@
+BEGIN;
+
+-- NULL означает, что значение неизвестно
+ALTER TABLE car ADD COLUMN IF NOT EXISTS color TEXT;
+ALTER TABLE car ADD COLUMN IF NOT EXISTS body_type TEXT;
+ALTER TABLE car ADD COLUMN IF NOT EXISTS fuel_type TEXT;
+ALTER TABLE car ADD COLUMN IF NOT EXISTS engine_volume INTEGER;
+ALTER TABLE car ADD COLUMN IF NOT EXISTS power INTEGER;
+ALTER TABLE car ADD COLUMN IF NOT EXISTS mileage INTEGER;
+ALTER TABLE car ADD COLUMN IF NOT EXISTS mileage_at TIMESTAMPTZ;
+
+ALTER TABLE car ADD CONSTRAINT check_car_body_type CHECK (
+    body_type IN ('sedan', 'hatchback', 'liftback', 'wagon', 'suv', 'coupe', 'convertible', 'minivan', 'pickup', 'van')
+);
+
+ALTER TABLE car ADD CONSTRAINT check_car_fuel_type CHECK (
+    fuel_type IN ('petrol', 'diesel', 'gas', 'hybrid', 'electric')
+);
+
+ALTER TABLE car ADD CONSTRAINT check_car_engine_volume CHECK (engine_volume > 0);
+ALTER TABLE car ADD CONSTRAINT check_car_power CHECK (power > 0);
+ALTER TABLE car ADD CONSTRAINT check_car_mileage CHECK (mileage >= 0);
+
+CREATE INDEX IF NOT EXISTS idx_car_body_type ON car (body_type);
+CREATE INDEX IF NOT EXISTS idx_car_fuel_type ON car (fuel_type);
+
+COMMIT;
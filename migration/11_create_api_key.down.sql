@@ -0,0 +1,5 @@
+BEGIN;
+
+DROP TABLE IF EXISTS api_key CASCADE;
+
+COMMIT;
@@ -0,0 +1,22 @@
+BEGIN;
+
+DROP TABLE IF EXISTS api_key CASCADE;
+CREATE TABLE api_key (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    -- Начало ключа, по нему ключ узнают в списке
+    prefix TEXT NOT NULL,
+    -- SHA-256 ключа в hex, сам ключ не хранится
+    hash TEXT NOT NULL,
+    scopes TEXT[] NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    expires_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ,
+    CONSTRAINT unique_api_key_name UNIQUE (name),
+    CONSTRAINT unique_api_key_hash UNIQUE (hash),
+    CONSTRAINT check_api_key_scopes CHECK (
+        cardinality(scopes) > 0 AND scopes <@ ARRAY['read', 'write', 'delete', 'admin']
+    )
+);
+
+COMMIT;
@@ -0,0 +1,14 @@
+BEGIN;
+
+DROP TABLE IF EXISTS enrichment_quota CASCADE;
+CREATE TABLE enrichment_quota (
+    client TEXT NOT NULL,
+    -- Сутки по UTC
+    day DATE NOT NULL,
+    used INTEGER NOT NULL CHECK (used >= 0),
+    PRIMARY KEY (client, day)
+);
+
+CREATE INDEX IF NOT EXISTS idx_enrichment_quota_day ON enrichment_quota (day);
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS audit CASCADE;
+DROP FUNCTION IF EXISTS audit_append_only() CASCADE;
+
+COMMIT;
@@ -0,0 +1,29 @@
+BEGIN;
+
+DROP TABLE IF EXISTS audit CASCADE;
+CREATE TABLE audit (
+    id BIGSERIAL PRIMARY KEY,
+    actor TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    entity TEXT NOT NULL,
+    entity_id BIGINT NOT NULL,
+    action TEXT NOT NULL,
+    before JSONB,
+    after JSONB
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_entity ON audit (entity, entity_id);
+CREATE INDEX IF NOT EXISTS idx_audit_actor ON audit (actor);
+CREATE INDEX IF NOT EXISTS idx_audit_created_at ON audit (created_at);
+
+CREATE OR REPLACE FUNCTION audit_append_only() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'audit is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_append_only
+    BEFORE UPDATE OR DELETE ON audit
+    FOR EACH ROW EXECUTE FUNCTION audit_append_only();
+
+COMMIT;
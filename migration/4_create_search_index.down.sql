@@ -0,0 +1,13 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_car_regnum_trgm CASCADE;
+DROP INDEX IF EXISTS idx_car_mark_trgm CASCADE;
+DROP INDEX IF EXISTS idx_car_model_trgm CASCADE;
+
+DROP INDEX IF EXISTS idx_owner_name_trgm CASCADE;
+DROP INDEX IF EXISTS idx_owner_surname_trgm CASCADE;
+DROP INDEX IF EXISTS idx_owner_patronymic_trgm CASCADE;
+
+DROP FUNCTION IF EXISTS search_normalize(TEXT) CASCADE;
+
+COMMIT;
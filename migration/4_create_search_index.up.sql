@@ -0,0 +1,20 @@
+BEGIN;
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+CREATE EXTENSION IF NOT EXISTS unaccent;
+
+-- unaccent() не IMMUTABLE, поэтому для индексов нужна обёртка
+-- с явно указанным словарём. Словарь также приводит "ё" к "е"
+CREATE OR REPLACE FUNCTION search_normalize(value TEXT) RETURNS TEXT AS $$
+    SELECT lower(public.unaccent('public.unaccent'::regdictionary, value))
+$$ LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT;
+
+CREATE INDEX IF NOT EXISTS idx_car_regnum_trgm ON car USING GIN (search_normalize(regNum) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_car_mark_trgm ON car USING GIN (search_normalize(mark) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_car_model_trgm ON car USING GIN (search_normalize(model) gin_trgm_ops);
+
+CREATE INDEX IF NOT EXISTS idx_owner_name_trgm ON owner USING GIN (search_normalize(name) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_owner_surname_trgm ON owner USING GIN (search_normalize(surname) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_owner_patronymic_trgm ON owner USING GIN (search_normalize(patronymic) gin_trgm_ops);
+
+COMMIT;
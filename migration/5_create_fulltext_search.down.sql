@@ -0,0 +1,9 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_car_search_vector CASCADE;
+DROP INDEX IF EXISTS idx_owner_search_vector CASCADE;
+
+ALTER TABLE car DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE owner DROP COLUMN IF EXISTS search_vector;
+
+COMMIT;
@@ -0,0 +1,21 @@
+BEGIN;
+
+ALTER TABLE car ADD COLUMN IF NOT EXISTS search_vector TSVECTOR
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', coalesce(regNum, '')), 'A') ||
+        setweight(to_tsvector('simple', coalesce(mark, '') || ' ' || coalesce(model, '')), 'B') ||
+        setweight(to_tsvector('russian', coalesce(mark, '') || ' ' || coalesce(model, '')), 'B')
+    ) STORED;
+
+ALTER TABLE owner ADD COLUMN IF NOT EXISTS search_vector TSVECTOR
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', coalesce(surname, '')), 'A') ||
+        setweight(to_tsvector('russian', coalesce(surname, '')), 'A') ||
+        setweight(to_tsvector('simple', coalesce(name, '') || ' ' || coalesce(patronymic, '')), 'C') ||
+        setweight(to_tsvector('russian', coalesce(name, '') || ' ' || coalesce(patronymic, '')), 'C')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_car_search_vector ON car USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS idx_owner_search_vector ON owner USING GIN (search_vector);
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+-- Исходное написание номеров не сохраняется, откатывать нечего
+
+COMMIT;
@@ -0,0 +1,35 @@
+BEGIN;
+
+-- Приводим сохранённые номера к каноническому виду: без пробелов
+-- и дефисов, в верхнем регистре, похожие латинские буквы кириллицей.
+-- Дипломатические номера с латинскими CD, D, T не трогаем, как и строки,
+-- которые после приведения совпали бы с уже существующим автомобилем
+UPDATE car
+SET regNum = normalized.canonical
+FROM (
+    SELECT DISTINCT ON (canonical, mark, model, year) id, canonical
+    FROM (
+        SELECT
+            id, mark, model, year,
+            translate(
+                upper(regexp_replace(regNum, '[[:space:]-]', '', 'g')),
+                'ABEKMHOPCTYX',
+                'АВЕКМНОРСТУХ'
+            ) AS canonical
+        FROM car
+        WHERE regNum !~* '^[0-9]{3}[[:space:]-]*(CD|D|T)'
+    ) candidates
+    ORDER BY canonical, mark, model, year, id
+) normalized
+WHERE car.id = normalized.id
+  AND car.regNum <> normalized.canonical
+  AND NOT EXISTS (
+      SELECT 1
+      FROM car other
+      WHERE other.regNum = normalized.canonical
+        AND other.mark = car.mark
+        AND other.model = car.model
+        AND other.year IS NOT DISTINCT FROM car.year
+  );
+
+COMMIT;
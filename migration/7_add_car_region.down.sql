@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_car_region CASCADE;
+
+ALTER TABLE car DROP COLUMN IF EXISTS region;
+
+COMMIT;
@@ -0,0 +1,49 @@
+BEGIN;
+
+ALTER TABLE car ADD COLUMN IF NOT EXISTS region TEXT;
+
+-- Код региона — последние две-три цифры номера. Выбор повторяет
+-- plate.Parse: форматы перебираются в том же порядке, прицеп раньше такси,
+-- и первым берётся формат с известным кодом региона: АВ123477 — это
+-- и прицеп с регионом 77, и такси с регионом 477. Шаблоны совпадают
+-- с pkg/plate, список кодов — ключи regions из pkg/plate/region.go
+WITH candidate AS (
+    SELECT car.id, format.priority, format.code
+    FROM car
+    CROSS JOIN LATERAL (VALUES
+        (1, (regexp_match(car.regNum, '^[АВЕКМНОРСТУХ](?:00[1-9]|0[1-9][0-9]|[1-9][0-9]{2})[АВЕКМНОРСТУХ]{2}([1-9][0-9]{2}|0[1-9]|[1-9][0-9])$'))[1]),
+        (2, (regexp_match(car.regNum, '^[АВЕКМНОРСТУХ]{2}[0-9]{4}([1-9][0-9]{2}|0[1-9]|[1-9][0-9])$'))[1]),
+        (3, (regexp_match(car.regNum, '^[АВЕКМНОРСТУХ]{2}[0-9]{3}([1-9][0-9]{2}|0[1-9]|[1-9][0-9])$'))[1]),
+        (4, (regexp_match(car.regNum, '^[0-9]{4}[АВЕКМНОРСТУХ]{2}([1-9][0-9]{2}|0[1-9]|[1-9][0-9])$'))[1]),
+        (5, (regexp_match(car.regNum, '^[АВЕКМНОРСТУХ][0-9]{4}([1-9][0-9]{2}|0[1-9]|[1-9][0-9])$'))[1]),
+        (6, (regexp_match(car.regNum, '^[0-9]{4}[АВЕКМНОРСТУХ]([1-9][0-9]{2}|0[1-9]|[1-9][0-9])$'))[1]),
+        (7, (regexp_match(car.regNum, '^[АВЕКМНОРСТУХ]{2}[0-9]{3}[АВЕКМНОРСТУХ]([1-9][0-9]{2}|0[1-9]|[1-9][0-9])$'))[1]),
+        (8, (regexp_match(car.regNum, '^[0-9]{3}CD[0-9]([1-9][0-9]{2}|0[1-9]|[1-9][0-9])$'))[1]),
+        (9, (regexp_match(car.regNum, '^[0-9]{3}[DT][0-9]{3}([1-9][0-9]{2}|0[1-9]|[1-9][0-9])$'))[1])
+    ) AS format (priority, code)
+    WHERE car.region IS NULL AND format.code IS NOT NULL
+),
+chosen AS (
+    SELECT DISTINCT ON (id) id, code
+    FROM candidate
+    ORDER BY id, code = ANY (ARRAY[
+        '01', '02', '03', '04', '05', '06', '07', '08', '09', '10', '11', '12', '13', '14', '15', '16',
+        '17', '18', '19', '20', '21', '22', '23', '24', '25', '26', '27', '28', '29', '30', '31', '32',
+        '33', '34', '35', '36', '37', '38', '39', '40', '41', '42', '43', '44', '45', '46', '47', '48',
+        '49', '50', '51', '52', '53', '54', '55', '56', '57', '58', '59', '60', '61', '62', '63', '64',
+        '65', '66', '67', '68', '69', '70', '71', '72', '73', '74', '75', '76', '77', '78', '79', '80',
+        '81', '82', '83', '84', '85', '86', '87', '89', '90', '91', '92', '93', '94', '95', '96', '97',
+        '98', '99', '102', '103', '113', '116', '118', '121', '122', '123', '124', '125', '126', '134', '136', '138',
+        '142', '147', '150', '152', '154', '155', '156', '159', '161', '163', '164', '173', '174', '177', '178', '180',
+        '181', '184', '185', '186', '190', '193', '196', '197', '198', '199', '702', '716', '725', '750', '761', '763',
+        '774', '777', '790', '797', '799', '977'
+    ]) DESC, priority
+)
+UPDATE car
+SET region = chosen.code
+FROM chosen
+WHERE car.id = chosen.id;
+
+CREATE INDEX IF NOT EXISTS idx_car_region ON car (region);
+
+COMMIT;
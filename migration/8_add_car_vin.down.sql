@@ -0,0 +1,8 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_car_vin_trgm CASCADE;
+
+ALTER TABLE car DROP CONSTRAINT IF EXISTS unique_car_vin;
+ALTER TABLE car DROP COLUMN IF EXISTS vin;
+
+COMMIT;
@@ -0,0 +1,10 @@
+BEGIN;
+
+ALTER TABLE car ADD COLUMN IF NOT EXISTS vin TEXT;
+
+-- NULL не участвует в уникальности, поэтому автомобили без VIN не конфликтуют
+ALTER TABLE car ADD CONSTRAINT unique_car_vin UNIQUE (vin);
+
+CREATE INDEX IF NOT EXISTS idx_car_vin_trgm ON car USING GIN (search_normalize(vin) gin_trgm_ops);
+
+COMMIT;
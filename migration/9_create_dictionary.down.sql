@@ -0,0 +1,16 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_car_mark_id CASCADE;
+DROP INDEX IF EXISTS idx_car_model_id CASCADE;
+
+ALTER TABLE car DROP COLUMN IF EXISTS model_id;
+ALTER TABLE car DROP COLUMN IF EXISTS mark_id;
+
+DROP TABLE IF EXISTS model_alias CASCADE;
+DROP TABLE IF EXISTS model CASCADE;
+DROP TABLE IF EXISTS mark_alias CASCADE;
+DROP TABLE IF EXISTS mark CASCADE;
+
+DROP FUNCTION IF EXISTS dictionary_key(TEXT) CASCADE;
+
+COMMIT;
@@ -0,0 +1,156 @@
+BEGIN;
+
+-- Ключ сравнения с синонимами: без регистра, диакритики, пробелов и дефисов,
+-- чтобы "Mercedes-Benz", "mercedes benz" и "MERCEDESBENZ" совпадали
+CREATE OR REPLACE FUNCTION dictionary_key(value TEXT) RETURNS TEXT AS $$
+    SELECT regexp_replace(search_normalize(value), '[[:space:]_-]+', '', 'g')
+$$ LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT;
+
+DROP TABLE IF EXISTS mark CASCADE;
+CREATE TABLE mark (
+    id SERIAL PRIMARY KEY,
+    name TEXT NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS unique_mark ON mark (dictionary_key(name));
+
+DROP TABLE IF EXISTS mark_alias CASCADE;
+CREATE TABLE mark_alias (
+    id SERIAL PRIMARY KEY,
+    mark_id INTEGER NOT NULL REFERENCES mark(id) ON DELETE CASCADE,
+    alias TEXT NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS unique_mark_alias ON mark_alias (dictionary_key(alias));
+
+DROP TABLE IF EXISTS model CASCADE;
+CREATE TABLE model (
+    id SERIAL PRIMARY KEY,
+    mark_id INTEGER NOT NULL REFERENCES mark(id) ON DELETE CASCADE,
+    name TEXT NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS unique_model ON model (mark_id, dictionary_key(name));
+
+DROP TABLE IF EXISTS model_alias CASCADE;
+CREATE TABLE model_alias (
+    id SERIAL PRIMARY KEY,
+    model_id INTEGER NOT NULL REFERENCES model(id) ON DELETE CASCADE,
+    alias TEXT NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS unique_model_alias ON model_alias (model_id, dictionary_key(alias));
+
+-- Ссылки пусты, если марка или модель не найдены в справочнике
+ALTER TABLE car ADD COLUMN IF NOT EXISTS mark_id INTEGER REFERENCES mark(id) ON DELETE SET NULL;
+ALTER TABLE car ADD COLUMN IF NOT EXISTS model_id INTEGER REFERENCES model(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_car_mark_id ON car (mark_id);
+CREATE INDEX IF NOT EXISTS idx_car_model_id ON car (model_id);
+
+INSERT INTO mark (name) VALUES
+    ('Lada'), ('УАЗ'), ('ГАЗ'), ('Toyota'), ('Volkswagen'), ('Hyundai'), ('Kia'),
+    ('BMW'), ('Mercedes-Benz'), ('Renault'), ('Škoda'), ('Nissan'), ('Ford'), ('Chevrolet');
+
+INSERT INTO mark_alias (mark_id, alias)
+SELECT mark.id, aliases.alias
+FROM (VALUES
+    ('Lada', 'ВАЗ'), ('Lada', 'VAZ'), ('Lada', 'Лада'), ('Lada', 'АвтоВАЗ'), ('Lada', 'Жигули'),
+    ('УАЗ', 'UAZ'),
+    ('ГАЗ', 'GAZ'),
+    ('Toyota', 'Тойота'),
+    ('Volkswagen', 'VW'), ('Volkswagen', 'Фольксваген'),
+    ('Hyundai', 'Хендай'), ('Hyundai', 'Хундай'), ('Hyundai', 'Хёндэ'),
+    ('Kia', 'Киа'),
+    ('BMW', 'БМВ'),
+    ('Mercedes-Benz', 'Mercedes'), ('Mercedes-Benz', 'Мерседес'), ('Mercedes-Benz', 'Мерседес-Бенц'),
+    ('Renault', 'Рено'),
+    ('Škoda', 'Шкода'),
+    ('Nissan', 'Ниссан'),
+    ('Ford', 'Форд'),
+    ('Chevrolet', 'Шевроле')
+) AS aliases (mark, alias)
+JOIN mark ON mark.name = aliases.mark;
+
+INSERT INTO model (mark_id, name)
+SELECT mark.id, models.model
+FROM (VALUES
+    ('Lada', 'Granta'), ('Lada', 'Vesta'), ('Lada', 'Niva'), ('Lada', 'Priora'), ('Lada', 'Kalina'),
+    ('Toyota', 'Camry'), ('Toyota', 'Corolla'), ('Toyota', 'RAV4'),
+    ('Volkswagen', 'Polo'), ('Volkswagen', 'Tiguan'),
+    ('Hyundai', 'Solaris'), ('Hyundai', 'Creta'),
+    ('Kia', 'Rio'), ('Kia', 'Sportage'),
+    ('Renault', 'Logan'), ('Renault', 'Duster'),
+    ('Škoda', 'Octavia'), ('Škoda', 'Rapid')
+) AS models (mark, model)
+JOIN mark ON mark.name = models.mark;
+
+INSERT INTO model_alias (model_id, alias)
+SELECT model.id, aliases.alias
+FROM (VALUES
+    ('Lada', 'Granta', 'Гранта'), ('Lada', 'Vesta', 'Веста'), ('Lada', 'Niva', 'Нива'),
+    ('Lada', 'Priora', 'Приора'), ('Lada', 'Priora', '2170'),
+    ('Lada', 'Kalina', 'Калина'), ('Lada', 'Kalina', '1118'),
+    ('Toyota', 'Camry', 'Камри'), ('Toyota', 'Corolla', 'Королла'), ('Toyota', 'RAV4', 'Рав4'),
+    ('Volkswagen', 'Polo', 'Поло'), ('Volkswagen', 'Tiguan', 'Тигуан'),
+    ('Hyundai', 'Solaris', 'Солярис'), ('Hyundai', 'Creta', 'Крета'),
+    ('Kia', 'Rio', 'Рио'), ('Kia', 'Sportage', 'Спортейдж'),
+    ('Renault', 'Logan', 'Логан'), ('Renault', 'Duster', 'Дастер'),
+    ('Škoda', 'Octavia', 'Октавия'), ('Škoda', 'Rapid', 'Рапид')
+) AS aliases (mark, model, alias)
+JOIN mark ON mark.name = aliases.mark
+JOIN model ON model.mark_id = mark.id AND model.name = aliases.model;
+
+-- Привязываем существующие автомобили к справочнику
+UPDATE car
+SET mark_id = mark.id
+FROM mark
+WHERE dictionary_key(car.mark) = dictionary_key(mark.name)
+   OR EXISTS (
+       SELECT 1
+       FROM mark_alias
+       WHERE mark_alias.mark_id = mark.id
+         AND dictionary_key(mark_alias.alias) = dictionary_key(car.mark)
+   );
+
+UPDATE car
+SET model_id = model.id
+FROM model
+WHERE model.mark_id = car.mark_id
+  AND (
+      dictionary_key(car.model) = dictionary_key(model.name)
+      OR EXISTS (
+          SELECT 1
+          FROM model_alias
+          WHERE model_alias.model_id = model.id
+            AND dictionary_key(model_alias.alias) = dictionary_key(car.model)
+      )
+  );
+
+-- Приводим названия к каноническим. Строки, которые после этого
+-- совпали бы с уже существующим автомобилем, оставляем как есть
+UPDATE car
+SET mark = canonical.mark, model = canonical.model
+FROM (
+    SELECT DISTINCT ON (car.regNum, mark.name, coalesce(model.name, car.model), car.year)
+        car.id,
+        mark.name AS mark,
+        coalesce(model.name, car.model) AS model
+    FROM car
+    JOIN mark ON mark.id = car.mark_id
+    LEFT JOIN model ON model.id = car.model_id
+    ORDER BY car.regNum, mark.name, coalesce(model.name, car.model), car.year, car.id
+) canonical
+WHERE car.id = canonical.id
+  AND (car.mark <> canonical.mark OR car.model <> canonical.model)
+  AND NOT EXISTS (
+      SELECT 1
+      FROM car other
+      WHERE other.id <> car.id
+        AND other.regNum = car.regNum
+        AND other.mark = canonical.mark
+        AND other.model = canonical.model
+        AND other.year IS NOT DISTINCT FROM car.year
+  );
+
+COMMIT;
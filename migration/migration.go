@@ -0,0 +1,35 @@
+// Package migration встраивает миграции в сборку, чтобы сервис
+// знал, до какой версии должна быть обновлена база
+package migration
+
+import (
+	"embed"
+	"strconv"
+	"strings"
+)
+
+//go:embed *.up.sql
+var files embed.FS
+
+// Latest возвращает номер последней миграции
+func Latest() uint {
+	entries, _ := files.ReadDir(".")
+
+	var latest uint
+
+	for _, entry := range entries {
+		prefix, _, ok := strings.Cut(entry.Name(), "_")
+		if !ok {
+			continue
+		}
+
+		version, err := strconv.ParseUint(prefix, 10, 64)
+		if err != nil {
+			continue
+		}
+
+		latest = max(latest, uint(version))
+	}
+
+	return latest
+}
@@ -0,0 +1,91 @@
+package migration
+
+import (
+	"fmt"
+	"github.com/jackvonhouse/car-enrichment/pkg/plate"
+	"regexp"
+	"slices"
+	"testing"
+)
+
+// regionMigration повторяет выбор региона из plate.Parse на SQL,
+// тест следит, чтобы шаблоны и список кодов не разошлись с pkg/plate
+const regionMigration = "7_add_car_region.up.sql"
+
+var (
+	sqlPattern = regexp.MustCompile(`regexp_match\(car\.regNum, '([^']+)'\)`)
+	sqlCodes   = regexp.MustCompile(`(?s)ARRAY\[(.*?)\]`)
+	sqlCode    = regexp.MustCompile(`'([0-9]+)'`)
+)
+
+func TestRegionBackfillMatchesPlate(t *testing.T) {
+	content, err := files.ReadFile(regionMigration)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	patterns := make([]*regexp.Regexp, 0)
+	for _, match := range sqlPattern.FindAllStringSubmatch(string(content), -1) {
+		patterns = append(patterns, regexp.MustCompile(match[1]))
+	}
+
+	array := sqlCodes.FindStringSubmatch(string(content))
+	if array == nil {
+		t.Fatal("known region codes not found")
+	}
+
+	known := make([]string, 0)
+	for _, match := range sqlCode.FindAllStringSubmatch(array[1], -1) {
+		known = append(known, match[1])
+	}
+
+	for i := 1; i < 1000; i++ {
+		for _, code := range []string{fmt.Sprintf("%02d", i), fmt.Sprintf("%03d", i)} {
+			_, ok := plate.Subject(code)
+			if ok != slices.Contains(known, code) {
+				t.Errorf("region %s: known in plate %t, in migration %t", code, ok, !ok)
+			}
+		}
+	}
+
+	// backfill — выбор из миграции: первый по порядку формат
+	// с известным регионом, иначе первый подходящий
+	backfill := func(number string) string {
+		first := ""
+
+		for _, pattern := range patterns {
+			match := pattern.FindStringSubmatch(number)
+			if match == nil {
+				continue
+			}
+
+			if slices.Contains(known, match[1]) {
+				return match[1]
+			}
+
+			if first == "" {
+				first = match[1]
+			}
+		}
+
+		return first
+	}
+
+	numbers := []string{
+		"А123ВС77", "А123ВС799", "А000ВС77", "А123ВС00", "А123ВС077",
+		"АВ12377", "АВ123477", "АВ1234177", "АВ123400", "АВ123700",
+		"1234АВ77", "А123478", "1234А178", "АВ123С77",
+		"001CD177", "001D00177", "001T00177", "12345677",
+	}
+
+	for _, number := range numbers {
+		want := ""
+		if p, err := plate.Parse(number); err == nil {
+			want = p.Region
+		}
+
+		if got := backfill(number); got != want {
+			t.Errorf("%s: plate.Parse region %q, migration %q", number, want, got)
+		}
+	}
+}
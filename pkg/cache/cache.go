@@ -0,0 +1,74 @@
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+type entry[V any] struct {
+	value     V
+	expiresAt time.Time
+}
+
+// Cache — потокобезопасный кэш в памяти, записи которого
+// устаревают через ttl после добавления
+type Cache[K comparable, V any] struct {
+	mu    sync.Mutex
+	ttl   time.Duration
+	items map[K]entry[V]
+}
+
+func New[K comparable, V any](
+	ttl time.Duration,
+) *Cache[K, V] {
+
+	return &Cache[K, V]{
+		ttl:   ttl,
+		items: make(map[K]entry[V]),
+	}
+}
+
+func (c *Cache[K, V]) Get(
+	key K,
+) (V, bool) {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.items[key]
+	if !ok || time.Now().After(e.expiresAt) {
+		var zero V
+
+		return zero, false
+	}
+
+	return e.value, true
+}
+
+// Set добавляет запись и заодно удаляет устаревшие,
+// чтобы кэш не рос от однократных ключей
+func (c *Cache[K, V]) Set(
+	key K,
+	value V,
+) {
+
+	if c.ttl <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+
+	for k, e := range c.items {
+		if now.After(e.expiresAt) {
+			delete(c.items, k)
+		}
+	}
+
+	c.items[key] = entry[V]{
+		value:     value,
+		expiresAt: now.Add(c.ttl),
+	}
+}
@@ -0,0 +1,34 @@
+package log
+
+import "context"
+
+type contextKey struct{}
+
+// ContextWithField добавляет поле, которое попадёт в каждую запись
+// логгера, полученного через Logger.WithContext
+func ContextWithField(
+	ctx context.Context,
+	key string,
+	value any,
+) context.Context {
+
+	parent := FieldsFromContext(ctx)
+
+	fields := make(map[string]any, len(parent)+1)
+	for k, v := range parent {
+		fields[k] = v
+	}
+
+	fields[key] = value
+
+	return context.WithValue(ctx, contextKey{}, fields)
+}
+
+func FieldsFromContext(
+	ctx context.Context,
+) map[string]any {
+
+	fields, _ := ctx.Value(contextKey{}).(map[string]any)
+
+	return fields
+}
@@ -1,8 +1,12 @@
 package log
 
+import "context"
+
 type Logger interface {
 	WithField(key string, value any) Logger
 	WithFields(fields map[string]any) Logger
+	// WithContext добавляет поля из контекста, например идентификатор запроса
+	WithContext(ctx context.Context) Logger
 
 	Debug(args ...any)
 	Debugf(format string, args ...any)
@@ -1,6 +1,7 @@
 package log
 
 import (
+	"context"
 	"os"
 
 	"github.com/sirupsen/logrus"
@@ -33,3 +34,12 @@ func (l *logrusAdapter) WithFields(fields map[string]any) Logger {
 
 	return &logrusAdapter{logger}
 }
+
+func (l *logrusAdapter) WithContext(ctx context.Context) Logger {
+	fields := FieldsFromContext(ctx)
+	if len(fields) == 0 {
+		return l
+	}
+
+	return &logrusAdapter{l.Entry.WithFields(fields)}
+}
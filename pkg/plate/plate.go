@@ -0,0 +1,161 @@
+package plate
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var ErrInvalid = errors.New("invalid license plate")
+
+type Kind string
+
+const (
+	// KindPrivate — А123ВС77
+	KindPrivate Kind = "private"
+	// KindTaxi — АВ12377, такси и маршрутный транспорт
+	KindTaxi Kind = "taxi"
+	// KindTrailer — АВ123477, прицепы и полуприцепы
+	KindTrailer Kind = "trailer"
+	// KindMotorcycle — 1234АВ77. Военные номера состоят из тех же
+	// символов и по строке от мотоциклетных не отличаются,
+	// поэтому также разбираются с этим видом
+	KindMotorcycle Kind = "motorcycle"
+	// KindPolice — А123477 и 1234А77
+	KindPolice Kind = "police"
+	// KindTransit — АВ123С77
+	KindTransit Kind = "transit"
+	// KindDiplomatic — 001CD177, 001D00177, 001T00177
+	KindDiplomatic Kind = "diplomatic"
+)
+
+type Plate struct {
+	// Number — канонический вид: без пробелов, в верхнем регистре,
+	// буквы кириллицей (кроме латинских CD, D, T дипломатических номеров)
+	Number string
+	Kind   Kind
+	// Region — код региона, например 77 или 799
+	Region string
+}
+
+func (p Plate) String() string { return p.Number }
+
+type format struct {
+	kind    Kind
+	pattern *regexp.Regexp
+	latin   bool
+}
+
+const (
+	letter = "[АВЕКМНОРСТУХ]"
+	region = "([1-9][0-9]{2}|0[1-9]|[1-9][0-9])"
+)
+
+var regionPattern = regexp.MustCompile("^" + region + "$")
+
+// Некоторые номера подходят под несколько форматов: АВ123477 — это
+// и прицеп с регионом 77, и такси с регионом 477. Выбирается формат
+// с известным кодом региона, при равенстве — первый по порядку
+var formats = []format{
+	{KindPrivate, regexp.MustCompile("^" + letter + "(?:00[1-9]|0[1-9][0-9]|[1-9][0-9]{2})" + letter + "{2}" + region + "$"), false},
+	{KindTrailer, regexp.MustCompile("^" + letter + "{2}[0-9]{4}" + region + "$"), false},
+	{KindTaxi, regexp.MustCompile("^" + letter + "{2}[0-9]{3}" + region + "$"), false},
+	{KindMotorcycle, regexp.MustCompile("^[0-9]{4}" + letter + "{2}" + region + "$"), false},
+	{KindPolice, regexp.MustCompile("^" + letter + "[0-9]{4}" + region + "$"), false},
+	{KindPolice, regexp.MustCompile("^[0-9]{4}" + letter + region + "$"), false},
+	{KindTransit, regexp.MustCompile("^" + letter + "{2}[0-9]{3}" + letter + region + "$"), false},
+	{KindDiplomatic, regexp.MustCompile("^[0-9]{3}CD[0-9]" + region + "$"), true},
+	{KindDiplomatic, regexp.MustCompile("^[0-9]{3}[DT][0-9]{3}" + region + "$"), true},
+}
+
+var (
+	toCyrillic = strings.NewReplacer(
+		"A", "А", "B", "В", "E", "Е", "K", "К", "M", "М", "H", "Н",
+		"O", "О", "P", "Р", "C", "С", "T", "Т", "Y", "У", "X", "Х",
+	)
+
+	toLatin = strings.NewReplacer(
+		"А", "A", "В", "B", "Е", "E", "К", "K", "М", "M", "Н", "H",
+		"О", "O", "Р", "P", "С", "C", "Т", "T", "У", "Y", "Х", "X",
+	)
+)
+
+// Parse разбирает номер по форматам ГОСТ Р 50577 и приводит его
+// к каноническому виду: убирает пробелы и дефисы, переводит
+// в верхний регистр и заменяет похожие латинские буквы кириллицей
+func Parse(
+	value string,
+) (Plate, error) {
+
+	compact := compact(value)
+
+	// Суффикс RUS встречается при ручном вводе номера с таблички,
+	// в том числе кириллицей или похожими латинскими буквами: РУС, PYC
+	if suffix := toCyrillic.Replace(compact); strings.HasSuffix(suffix, "RUS") || strings.HasSuffix(suffix, "РУС") {
+		runes := []rune(compact)
+		compact = string(runes[:len(runes)-3])
+	}
+
+	candidates := map[bool]string{
+		false: toCyrillic.Replace(compact),
+		true:  toLatin.Replace(compact),
+	}
+
+	var parsed *Plate
+
+	for _, f := range formats {
+		number := candidates[f.latin]
+
+		match := f.pattern.FindStringSubmatch(number)
+		if match == nil {
+			continue
+		}
+
+		p := Plate{
+			Number: number,
+			Kind:   f.kind,
+			Region: match[len(match)-1],
+		}
+
+		if _, ok := Subject(p.Region); ok {
+			return p, nil
+		}
+
+		if parsed == nil {
+			parsed = &p
+		}
+	}
+
+	if parsed == nil {
+		return Plate{}, ErrInvalid
+	}
+
+	return *parsed, nil
+}
+
+// Normalize приводит к каноническому виду произвольную, в том числе
+// неполную строку номера, например для поиска по подстроке
+func Normalize(
+	value string,
+) string {
+
+	if p, err := Parse(value); err == nil {
+		return p.Number
+	}
+
+	return toCyrillic.Replace(compact(value))
+}
+
+func compact(
+	value string,
+) string {
+
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) || r == '-' {
+			return -1
+		}
+
+		return unicode.ToUpper(r)
+	}, value)
+}
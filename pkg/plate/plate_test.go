@@ -0,0 +1,133 @@
+package plate
+
+import (
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  string
+		number string
+		kind   Kind
+		region string
+	}{
+		{"private", "А123ВС77", "А123ВС77", KindPrivate, "77"},
+		{"private three-digit region", "А123ВС799", "А123ВС799", KindPrivate, "799"},
+		{"private leading zero region", "А001ВС01", "А001ВС01", KindPrivate, "01"},
+		{"latin look-alikes", "A123BC77", "А123ВС77", KindPrivate, "77"},
+		{"lowercase latin", "a123bc77", "А123ВС77", KindPrivate, "77"},
+		{"lowercase cyrillic", "а123вс77", "А123ВС77", KindPrivate, "77"},
+		{"mixed alphabets", "А123BС77", "А123ВС77", KindPrivate, "77"},
+		{"spaces and dashes", " А 123 ВС-77 ", "А123ВС77", KindPrivate, "77"},
+		{"latin rus suffix", "А123ВС77RUS", "А123ВС77", KindPrivate, "77"},
+		{"lowercase rus suffix", "a123bc 77 rus", "А123ВС77", KindPrivate, "77"},
+		{"cyrillic rus suffix", "А123ВС77РУС", "А123ВС77", KindPrivate, "77"},
+		{"look-alike rus suffix", "А123ВС77PYC", "А123ВС77", KindPrivate, "77"},
+		{"rus suffix after three-digit region", "А123ВС777 RUS", "А123ВС777", KindPrivate, "777"},
+		{"taxi", "АВ12377", "АВ12377", KindTaxi, "77"},
+		{"trailer", "АВ1234177", "АВ1234177", KindTrailer, "177"},
+		// Прицеп с регионом 77 или такси с регионом 477: выбирается известный регион
+		{"trailer or taxi", "АВ123477", "АВ123477", KindTrailer, "77"},
+		{"trailer or taxi rus", "АВ123477RUS", "АВ123477", KindTrailer, "77"},
+		// Мотоциклетные и военные номера не различаются
+		{"motorcycle", "1234АВ77", "1234АВ77", KindMotorcycle, "77"},
+		{"motorcycle latin", "1234ab50", "1234АВ50", KindMotorcycle, "50"},
+		{"police letter first", "А123478", "А123478", KindPolice, "78"},
+		{"police digits first", "1234А178", "1234А178", KindPolice, "178"},
+		{"transit", "АВ123С77", "АВ123С77", KindTransit, "77"},
+		{"diplomatic cd", "001CD177", "001CD177", KindDiplomatic, "77"},
+		{"diplomatic cd cyrillic", "001СD177", "001CD177", KindDiplomatic, "77"},
+		{"diplomatic d", "001D00177", "001D00177", KindDiplomatic, "77"},
+		{"diplomatic t", "001Т00177", "001T00177", KindDiplomatic, "77"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := Parse(tt.value)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if p.Number != tt.number || p.Kind != tt.kind || p.Region != tt.region {
+				t.Errorf(
+					"expected %s %s %s, got %s %s %s",
+					tt.number, tt.kind, tt.region, p.Number, p.Kind, p.Region,
+				)
+			}
+		})
+	}
+}
+
+func TestParseRejects(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{"empty", ""},
+		{"only rus", "RUS"},
+		{"one-digit region", "А123ВС7"},
+		{"four-digit region", "А123ВС7777"},
+		{"zero region", "А123ВС00"},
+		{"zero number", "А000ВС77"},
+		{"letter without latin look-alike", "Б123ВС77"},
+		{"latin letter without cyrillic look-alike", "D123BC77"},
+		{"trailing letter", "А123ВС77Б"},
+		{"digits only", "12345677"},
+		{"short", "А12ВС77"},
+		{"rus in the middle", "А123RUSВС77"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if p, err := Parse(tt.value); err != ErrInvalid {
+				t.Errorf("expected ErrInvalid, got %v (%+v)", err, p)
+			}
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := map[string]string{
+		"a123bc77": "А123ВС77",
+		"a123":     "А123",
+		" bc 77 ":  "ВС77",
+		"х":        "Х",
+	}
+
+	for value, want := range tests {
+		if got := Normalize(value); got != want {
+			t.Errorf("%q: expected %q, got %q", value, want, got)
+		}
+	}
+}
+
+func TestSubject(t *testing.T) {
+	if subject, ok := Subject("77"); !ok || subject == "" {
+		t.Errorf("expected subject for 77, got %q", subject)
+	}
+
+	if _, ok := Subject("477"); ok {
+		t.Error("expected no subject for 477")
+	}
+}
+
+func TestValidRegion(t *testing.T) {
+	tests := map[string]bool{
+		"77":   true,
+		"01":   true,
+		"177":  true,
+		"477":  true,
+		"7":    false,
+		"00":   false,
+		"077":  false,
+		"1777": false,
+		"":     false,
+	}
+
+	for code, want := range tests {
+		if got := ValidRegion(code); got != want {
+			t.Errorf("%q: expected %t, got %t", code, want, got)
+		}
+	}
+}
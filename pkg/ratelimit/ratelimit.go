@@ -0,0 +1,138 @@
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Limit — Requests запросов за Period с запасом Burst.
+// Без Burst запас равен Requests
+type Limit struct {
+	Requests int
+	Period   time.Duration
+	Burst    int
+}
+
+func (l Limit) Valid() bool {
+	return l.Requests > 0 && l.Period > 0
+}
+
+func (l Limit) capacity() float64 {
+	if l.Burst > 0 {
+		return float64(l.Burst)
+	}
+
+	return float64(l.Requests)
+}
+
+// perToken — время восстановления одного запроса
+func (l Limit) perToken() time.Duration {
+	return l.Period / time.Duration(l.Requests)
+}
+
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset — время до полного восстановления запаса
+	Reset time.Duration
+	// RetryAfter — время до следующего разрешённого запроса, если запрос отклонён
+	RetryAfter time.Duration
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// Limiter — token bucket с отдельным запасом на каждый ключ
+type Limiter struct {
+	mu      sync.Mutex
+	limit   Limit
+	buckets map[string]*bucket
+	swept   time.Time
+}
+
+func New(
+	limit Limit,
+) *Limiter {
+
+	return &Limiter{
+		limit:   limit,
+		buckets: make(map[string]*bucket),
+		swept:   time.Now(),
+	}
+}
+
+func (l *Limiter) Allow(
+	key string,
+) Result {
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	capacity := l.limit.capacity()
+	perToken := l.limit.perToken()
+
+	l.sweep(now, capacity, perToken)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: capacity, updated: now}
+		l.buckets[key] = b
+	}
+
+	b.tokens = math.Min(capacity, b.tokens+float64(now.Sub(b.updated))/float64(perToken))
+	b.updated = now
+
+	result := Result{Limit: l.limit.Requests}
+
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = time.Duration((1 - b.tokens) * float64(perToken))
+	}
+
+	result.Remaining = int(b.tokens)
+	result.Reset = time.Duration((capacity - b.tokens) * float64(perToken))
+
+	return result
+}
+
+// Refund возвращает в запас ключа запрос, взятый Allow, когда он
+// не должен учитываться: например, попытка входа оказалась удачной
+func (l *Limiter) Refund(
+	key string,
+) {
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if b, ok := l.buckets[key]; ok {
+		b.tokens = math.Min(l.limit.capacity(), b.tokens+1)
+	}
+}
+
+// sweep раз в период удаляет восстановившиеся запасы,
+// чтобы ключи разовых клиентов не копились в памяти
+func (l *Limiter) sweep(
+	now time.Time,
+	capacity float64,
+	perToken time.Duration,
+) {
+
+	if now.Sub(l.swept) < l.limit.Period {
+		return
+	}
+
+	l.swept = now
+
+	for key, b := range l.buckets {
+		if b.tokens+float64(now.Sub(b.updated))/float64(perToken) >= capacity {
+			delete(l.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package safe
+
+import (
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+)
+
+// PanicError — паника, перехваченная в Call, вместе со стеком горутины
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Unwrap позволяет найти ошибку, переданную в panic
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+
+	return err
+}
+
+// Call выполняет fn и превращает панику в *PanicError,
+// чтобы паника в фоновой горутине не останавливала процесс
+func Call(
+	fn func() error,
+) (err error) {
+
+	defer func() {
+		if value := recover(); value != nil {
+			err = &PanicError{
+				Value: value,
+				Stack: debug.Stack(),
+			}
+		}
+	}()
+
+	return fn()
+}
+
+// Go запускает fn в горутине через Call и учитывает её в wg.
+// Ошибка или паника передаётся в onError, если он задан
+func Go(
+	wg *sync.WaitGroup,
+	fn func() error,
+	onError func(error),
+) {
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		if err := Call(fn); err != nil && onError != nil {
+			onError(err)
+		}
+	}()
+}
+
+// Stack возвращает стек паники, если err получена из Call
+func Stack(
+	err error,
+) string {
+
+	var p *PanicError
+	if errors.As(err, &p) {
+		return string(p.Stack)
+	}
+
+	return ""
+}
@@ -0,0 +1,47 @@
+package shutdown
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// State отмечает начало остановки. Его Shutdown вызывается первым,
+// чтобы проверка готовности успела вывести сервис из балансировки
+// до закрытия сервера
+type State struct {
+	stopping atomic.Bool
+	// delay — пауза после отметки, за которую балансировщик замечает остановку
+	delay time.Duration
+}
+
+func NewState(
+	delay time.Duration,
+) *State {
+
+	return &State{
+		delay: delay,
+	}
+}
+
+func (s *State) Stopping() bool {
+	return s.stopping.Load()
+}
+
+func (s *State) Shutdown(
+	ctx context.Context,
+) error {
+
+	s.stopping.Store(true)
+
+	if s.delay <= 0 {
+		return nil
+	}
+
+	select {
+	case <-time.After(s.delay):
+	case <-ctx.Done():
+	}
+
+	return nil
+}
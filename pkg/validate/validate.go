@@ -0,0 +1,234 @@
+package validate
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// Error — нарушение правила в одном поле
+type Error struct {
+	Field   string
+	Message string
+}
+
+// Errors — все нарушения, найденные при проверке
+type Errors []Error
+
+func (e Errors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, err.Field+": "+err.Message)
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// Rule возвращает текст нарушения или пустую строку
+type Rule[T any] func(T) string
+
+// Validator собирает нарушения всех полей, а не только первого,
+// чтобы клиент исправил запрос за один раз
+type Validator struct {
+	errors Errors
+}
+
+func New() *Validator {
+	return &Validator{}
+}
+
+// Add добавляет нарушение, найденное без правил, например при разборе значения
+func (v *Validator) Add(
+	field string,
+	message string,
+) {
+
+	v.errors = append(v.errors, Error{Field: field, Message: message})
+}
+
+// Check добавляет нарушение, если ok ложно
+func (v *Validator) Check(
+	field string,
+	ok bool,
+	message string,
+) {
+
+	if !ok {
+		v.Add(field, message)
+	}
+}
+
+// Has сообщает, есть ли уже нарушение в поле. Зависимые
+// проверки пропускаются, если само поле некорректно
+func (v *Validator) Has(
+	field string,
+) bool {
+
+	return slices.ContainsFunc(v.errors, func(e Error) bool { return e.Field == field })
+}
+
+func (v *Validator) Valid() bool {
+	return len(v.errors) == 0
+}
+
+// Err возвращает Errors или nil, если нарушений нет
+func (v *Validator) Err() error {
+	if v.Valid() {
+		return nil
+	}
+
+	return v.errors
+}
+
+// Field проверяет значение правилами по порядку
+// до первого нарушения
+func Field[T any](
+	v *Validator,
+	field string,
+	value T,
+	rules ...Rule[T],
+) {
+
+	for _, rule := range rules {
+		if message := rule(value); message != "" {
+			v.Add(field, message)
+
+			return
+		}
+	}
+}
+
+// Optional проверяет значение, только если оно задано
+func Optional[T any](
+	v *Validator,
+	field string,
+	value *T,
+	rules ...Rule[T],
+) {
+
+	if value != nil {
+		Field(v, field, *value, rules...)
+	}
+}
+
+// Each проверяет каждый элемент, поле элемента — field[i]
+func Each[T any](
+	v *Validator,
+	field string,
+	values []T,
+	rules ...Rule[T],
+) {
+
+	for i, value := range values {
+		Field(v, fmt.Sprintf("%s[%d]", field, i), value, rules...)
+	}
+}
+
+func Required() Rule[string] {
+	return func(value string) string {
+		if strings.TrimSpace(value) == "" {
+			return "is required"
+		}
+
+		return ""
+	}
+}
+
+// Length ограничивает длину строки в символах, 0 в max снимает верхнюю границу
+func Length(
+	min, max int,
+) Rule[string] {
+
+	return func(value string) string {
+		length := utf8.RuneCountInString(value)
+
+		if length < min {
+			return fmt.Sprintf("must be at least %d characters long", min)
+		}
+
+		if max > 0 && length > max {
+			return fmt.Sprintf("must be at most %d characters long", max)
+		}
+
+		return ""
+	}
+}
+
+func Min[T cmp.Ordered](
+	min T,
+) Rule[T] {
+
+	return func(value T) string {
+		if value < min {
+			return fmt.Sprintf("must be at least %v", min)
+		}
+
+		return ""
+	}
+}
+
+func Range[T cmp.Ordered](
+	min, max T,
+) Rule[T] {
+
+	return func(value T) string {
+		if value < min || value > max {
+			return fmt.Sprintf("must be between %v and %v", min, max)
+		}
+
+		return ""
+	}
+}
+
+func NotEmpty[T any]() Rule[[]T] {
+	return func(values []T) string {
+		if len(values) == 0 {
+			return "must not be empty"
+		}
+
+		return ""
+	}
+}
+
+// OneOf допускает только перечисленные значения
+func OneOf[T comparable](
+	values ...T,
+) Rule[T] {
+
+	return func(value T) string {
+		if !slices.Contains(values, value) {
+			return fmt.Sprintf("must be one of %s", join(values))
+		}
+
+		return ""
+	}
+}
+
+// Parse проверяет значение функцией разбора, например plate.Parse,
+// и возвращает текст её ошибки
+func Parse[T, R any](
+	parse func(T) (R, error),
+) Rule[T] {
+
+	return func(value T) string {
+		if _, err := parse(value); err != nil {
+			return err.Error()
+		}
+
+		return ""
+	}
+}
+
+func join[T any](
+	values []T,
+) string {
+
+	parts := make([]string, 0, len(values))
+	for _, value := range values {
+		parts = append(parts, fmt.Sprint(value))
+	}
+
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,159 @@
+package vin
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var (
+	ErrInvalid    = errors.New("invalid vin")
+	ErrCheckDigit = errors.New("invalid vin check digit")
+)
+
+type VIN struct {
+	// Value — VIN в верхнем регистре без пробелов
+	Value        string
+	WMI          string
+	Manufacturer string
+	// ModelYear равен 0, если год не удалось определить
+	ModelYear int
+	// CheckDigitValid — совпадает ли 9-й символ с контрольной суммой
+	CheckDigitValid bool
+}
+
+func (v VIN) String() string { return v.Value }
+
+const length = 17
+
+var (
+	transliteration = map[rune]int{
+		'A': 1, 'B': 2, 'C': 3, 'D': 4, 'E': 5, 'F': 6, 'G': 7, 'H': 8,
+		'J': 1, 'K': 2, 'L': 3, 'M': 4, 'N': 5, 'P': 7, 'R': 9,
+		'S': 2, 'T': 3, 'U': 4, 'V': 5, 'W': 6, 'X': 7, 'Y': 8, 'Z': 9,
+	}
+
+	weights = [length]int{8, 7, 6, 5, 4, 3, 2, 10, 0, 9, 8, 7, 6, 5, 4, 3, 2}
+
+	// Коды 10-го символа повторяются каждые 30 лет, начиная с 1980
+	modelYears = "ABCDEFGHJKLMNPRSTVWXY123456789"
+)
+
+// Parse проверяет VIN по ISO 3779 и расшифровывает производителя и
+// модельный год. Контрольная цифра обязательна только для VIN
+// североамериканского рынка (первый символ 1–5), для остальных
+// её корректность лишь отражается в CheckDigitValid
+func Parse(
+	value string,
+) (VIN, error) {
+
+	normalized := Normalize(value)
+
+	if len(normalized) != length {
+		return VIN{}, ErrInvalid
+	}
+
+	// Только ASCII: другие цифры Unicode заняли бы в строке больше
+	// одного байта и сломали бы контрольную сумму
+	for _, c := range normalized {
+		if _, ok := transliteration[c]; !ok && (c < '0' || c > '9') {
+			return VIN{}, ErrInvalid
+		}
+	}
+
+	v := VIN{
+		Value:           normalized,
+		WMI:             normalized[:3],
+		CheckDigitValid: CheckDigit(normalized) == normalized[8],
+	}
+
+	if !v.CheckDigitValid && northAmerican(normalized) {
+		return VIN{}, ErrCheckDigit
+	}
+
+	v.Manufacturer, _ = Manufacturer(normalized)
+	v.ModelYear = modelYear(normalized, time.Now().Year()+1)
+
+	return v, nil
+}
+
+// Normalize переводит VIN в верхний регистр и убирает пробелы
+func Normalize(
+	value string,
+) string {
+
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) || r == '-' {
+			return -1
+		}
+
+		return unicode.ToUpper(r)
+	}, value)
+}
+
+// CheckDigit вычисляет контрольный символ VIN: цифру или X
+func CheckDigit(
+	vin string,
+) byte {
+
+	sum := 0
+
+	for i, c := range vin {
+		if i >= length {
+			break
+		}
+
+		value, ok := transliteration[c]
+		if !ok {
+			value = int(c - '0')
+		}
+
+		sum += value * weights[i]
+	}
+
+	remainder := sum % 11
+	if remainder == 10 {
+		return 'X'
+	}
+
+	return byte('0' + remainder)
+}
+
+func northAmerican(
+	vin string,
+) bool {
+
+	return vin[0] >= '1' && vin[0] <= '5'
+}
+
+// modelYear выбирает из кандидатов с шагом 30 лет последний,
+// не превышающий latest. Для Северной Америки 7-я позиция задаёт
+// цикл: буква — модельный год 2010 и позже, цифра — 1980–2009
+func modelYear(
+	vin string,
+	latest int,
+) int {
+
+	index := strings.IndexByte(modelYears, vin[9])
+	if index < 0 {
+		return 0
+	}
+
+	year := 1980 + index
+
+	// Цифра в 7-й позиции — модельный год до 2010
+	if northAmerican(vin) {
+		if unicode.IsLetter(rune(vin[6])) {
+			return year + 30
+		}
+
+		return year
+	}
+
+	for year+30 <= latest {
+		year += 30
+	}
+
+	return year
+}
@@ -0,0 +1,115 @@
+package vin
+
+import (
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name         string
+		value        string
+		want         string
+		manufacturer string
+		// Модельный год вне Северной Америки зависит от текущего года,
+		// 0 — не проверяется здесь, см. TestModelYear
+		modelYear  int
+		checkDigit bool
+	}{
+		{"north american", "1M8GDM9AXKP042788", "1M8GDM9AXKP042788", "", 1989, true},
+		{"north american since 2010", "5YJ3E1EA8KF000001", "5YJ3E1EA8KF000001", "Tesla", 2019, true},
+		{"lada", "XTA210997Y2712345", "XTA210997Y2712345", "Lada", 0, true},
+		{"two-char wmi", "JTDBR32E830012345", "JTDBR32E830012345", "Toyota", 0, true},
+		{"invalid check digit outside north america", "WVWZZZ1JZXW000001", "WVWZZZ1JZXW000001", "Volkswagen", 0, false},
+		{"lowercase with spaces", " xta 21099 7y2712345 ", "XTA210997Y2712345", "Lada", 0, true},
+		{"dashes", "XTA-210997-Y2712345", "XTA210997Y2712345", "Lada", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := Parse(tt.value)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if v.Value != tt.want {
+				t.Errorf("value: expected %s, got %s", tt.want, v.Value)
+			}
+
+			if v.Manufacturer != tt.manufacturer {
+				t.Errorf("manufacturer: expected %q, got %q", tt.manufacturer, v.Manufacturer)
+			}
+
+			if tt.modelYear != 0 && v.ModelYear != tt.modelYear {
+				t.Errorf("model year: expected %d, got %d", tt.modelYear, v.ModelYear)
+			}
+
+			if v.CheckDigitValid != tt.checkDigit {
+				t.Errorf("check digit valid: expected %t, got %t", tt.checkDigit, v.CheckDigitValid)
+			}
+		})
+	}
+}
+
+func TestParseRejects(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		err   error
+	}{
+		{"empty", "", ErrInvalid},
+		{"too short", "XTA210997Y271234", ErrInvalid},
+		{"too long", "XTA210997Y27123456", ErrInvalid},
+		{"letter I", "XTA21099IY2712345", ErrInvalid},
+		{"letter O", "XTA21099OY2712345", ErrInvalid},
+		{"letter Q", "XTA21099QY2712345", ErrInvalid},
+		{"cyrillic look-alike", "ХТА210997Y2712345", ErrInvalid},
+		{"arabic-indic digit", "1M8GDM9AXKP0427٣", ErrInvalid},
+		{"fullwidth digit", "1M8GDM9AXKP04278８", ErrInvalid},
+		{"punctuation", "XTA210997Y271234.", ErrInvalid},
+		{"north american check digit", "1M8GDM9A1KP042788", ErrCheckDigit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(tt.value); err != tt.err {
+				t.Errorf("expected %v, got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestCheckDigit(t *testing.T) {
+	tests := map[string]byte{
+		"1M8GDM9AXKP042788": 'X',
+		"XTA210997Y2712345": '7',
+		"JTDBR32E830012345": '8',
+	}
+
+	for vin, want := range tests {
+		if got := CheckDigit(vin); got != want {
+			t.Errorf("%s: expected %c, got %c", vin, want, got)
+		}
+	}
+}
+
+func TestModelYear(t *testing.T) {
+	tests := []struct {
+		vin    string
+		latest int
+		want   int
+	}{
+		{"XTA210997Y2712345", 2027, 2000},
+		{"XTA210997Y2712345", 2031, 2030},
+		{"WVWZZZ1JZXW000001", 2027, 1999},
+		{"XTA210997A2712345", 2027, 2010},
+		{"1M8GDM9AXKP042788", 2027, 1989},
+		{"5YJ3E1EA8KF000001", 2027, 2019},
+		{"XTA210997U2712345", 2027, 0},
+	}
+
+	for _, tt := range tests {
+		if got := modelYear(tt.vin, tt.latest); got != tt.want {
+			t.Errorf("%s up to %d: expected %d, got %d", tt.vin, tt.latest, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package vin
+
+// manufacturers сопоставляет WMI (первые символы VIN) производителям.
+// Сначала ищется полный трёхсимвольный код, затем двухсимвольный
+// префикс для производителей, зарегистрировавших целый диапазон
+var manufacturers = map[string]string{
+	// Россия
+	"XTA": "Lada",
+	"XTT": "УАЗ",
+	"XTH": "ГАЗ",
+	"X96": "ГАЗ",
+	"XTC": "КАМАЗ",
+	"X1M": "ПАЗ",
+	"X4X": "BMW",
+	"XW8": "Volkswagen",
+	"XW7": "Toyota",
+	"X7L": "Renault",
+	"Z8N": "Nissan",
+	"X9F": "Ford",
+	"Z94": "Hyundai",
+	"XUF": "Chevrolet",
+
+	// Европа
+	"WVW": "Volkswagen",
+	"WV1": "Volkswagen",
+	"WV2": "Volkswagen",
+	"WAU": "Audi",
+	"WBA": "BMW",
+	"WBS": "BMW",
+	"WDB": "Mercedes-Benz",
+	"WDD": "Mercedes-Benz",
+	"WF0": "Ford",
+	"W0L": "Opel",
+	"WP0": "Porsche",
+	"VF1": "Renault",
+	"VF3": "Peugeot",
+	"VF7": "Citroën",
+	"ZFA": "Fiat",
+	"YV1": "Volvo",
+	"SAJ": "Jaguar",
+	"SAL": "Land Rover",
+	"TMB": "Škoda",
+	"VSS": "SEAT",
+
+	// Азия
+	"JT":  "Toyota",
+	"JHM": "Honda",
+	"JN":  "Nissan",
+	"JM":  "Mazda",
+	"JF":  "Subaru",
+	"JS":  "Suzuki",
+	"JMB": "Mitsubishi",
+	"KMH": "Hyundai",
+	"KNA": "Kia",
+	"LSV": "Volkswagen",
+
+	// Северная Америка
+	"1FA": "Ford",
+	"1FT": "Ford",
+	"1G1": "Chevrolet",
+	"1HG": "Honda",
+	"2T":  "Toyota",
+	"5YJ": "Tesla",
+}
+
+// Manufacturer возвращает производителя по WMI
+func Manufacturer(
+	vin string,
+) (string, bool) {
+
+	if len(vin) < 3 {
+		return "", false
+	}
+
+	if manufacturer, ok := manufacturers[vin[:3]]; ok {
+		return manufacturer, true
+	}
+
+	manufacturer, ok := manufacturers[vin[:2]]
+
+	return manufacturer, ok
+}
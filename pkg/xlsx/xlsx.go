@@ -0,0 +1,198 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Writer построчно пишет книгу XLSX с одним листом прямо в io.Writer.
+// Строки не накапливаются в памяти: лист сжимается и отдаётся по мере
+// записи, а строки хранятся как inlineStr без общей таблицы строк
+type Writer struct {
+	zip   *zip.Writer
+	sheet *bufio.Writer
+	rows  int
+}
+
+var parts = []struct {
+	name    string
+	content string
+}{
+	{
+		"[Content_Types].xml",
+		`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+			`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+			`<Default Extension="xml" ContentType="application/xml"/>` +
+			`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+			`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+			`</Types>`,
+	},
+	{
+		"_rels/.rels",
+		`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+			`</Relationships>`,
+	},
+	{
+		"xl/workbook.xml",
+		`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets><sheet name="Sheet1" sheetId="1" r:id="rId1"/></sheets>` +
+			`</workbook>`,
+	},
+	{
+		"xl/_rels/workbook.xml.rels",
+		`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+			`</Relationships>`,
+	},
+}
+
+func NewWriter(
+	w io.Writer,
+) (*Writer, error) {
+
+	z := zip.NewWriter(w)
+
+	for _, part := range parts {
+		f, err := z.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := io.WriteString(f, part.content); err != nil {
+			return nil, err
+		}
+	}
+
+	// Лист создаётся последним: после него в архив ничего не пишется,
+	// поэтому его можно заполнять до самого Close
+	f, err := z.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+
+	sheet := bufio.NewWriter(f)
+
+	if _, err := sheet.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`); err != nil {
+		return nil, err
+	}
+
+	return &Writer{
+		zip:   z,
+		sheet: sheet,
+	}, nil
+}
+
+// Write добавляет строку. Числа записываются числовыми ячейками,
+// время — строкой RFC 3339, nil — пустой ячейкой, остальное — текстом
+func (w *Writer) Write(
+	cells []any,
+) error {
+
+	w.rows++
+
+	if _, err := fmt.Fprintf(w.sheet, `<row r="%d">`, w.rows); err != nil {
+		return err
+	}
+
+	for i, cell := range cells {
+		if err := w.cell(column(i)+strconv.Itoa(w.rows), cell); err != nil {
+			return err
+		}
+	}
+
+	_, err := w.sheet.WriteString(`</row>`)
+
+	return err
+}
+
+func (w *Writer) cell(
+	ref string,
+	value any,
+) error {
+
+	var number string
+
+	switch v := value.(type) {
+
+	case nil:
+		return nil
+
+	case int:
+		number = strconv.Itoa(v)
+
+	case int64:
+		number = strconv.FormatInt(v, 10)
+
+	case float64:
+		number = strconv.FormatFloat(v, 'f', -1, 64)
+
+	case time.Time:
+		value = v.Format(time.RFC3339)
+	}
+
+	if number != "" {
+		_, err := fmt.Fprintf(w.sheet, `<c r="%s"><v>%s</v></c>`, ref, number)
+
+		return err
+	}
+
+	if _, err := fmt.Fprintf(w.sheet, `<c r="%s" t="inlineStr"><is><t xml:space="preserve">`, ref); err != nil {
+		return err
+	}
+
+	if err := xml.EscapeText(w.sheet, []byte(fmt.Sprint(value))); err != nil {
+		return err
+	}
+
+	_, err := w.sheet.WriteString(`</t></is></c>`)
+
+	return err
+}
+
+// Flush отправляет накопленные данные в нижележащий io.Writer
+func (w *Writer) Flush() error {
+	if err := w.sheet.Flush(); err != nil {
+		return err
+	}
+
+	return w.zip.Flush()
+}
+
+// Close завершает лист и архив, но не закрывает нижележащий io.Writer
+func (w *Writer) Close() error {
+	if _, err := w.sheet.WriteString(`</sheetData></worksheet>`); err != nil {
+		return err
+	}
+
+	if err := w.sheet.Flush(); err != nil {
+		return err
+	}
+
+	return w.zip.Close()
+}
+
+// column переводит номер столбца с нуля в буквенное обозначение: 0 → A, 26 → AA
+func column(
+	index int,
+) string {
+
+	name := ""
+
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+
+	return name
+}